@@ -0,0 +1,141 @@
+package gnlib
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Catalog keeps message templates by language and key. Templates can
+// contain fmt verbs and the same tags as FormatMessage. English is used
+// as a fallback language.
+//
+// Catalog is meant to be filled during initialization of an application,
+// Add is not safe for concurrent use.
+type Catalog struct {
+	langs []language.Tag
+	msgs  map[language.Tag]map[string]string
+}
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		langs: []language.Tag{language.English},
+		msgs:  map[language.Tag]map[string]string{language.English: {}},
+	}
+}
+
+// Add registers templates for a language given as a BCP 47 tag, for
+// example "en", "pt-BR" or "zh-Hans". Templates for existing keys are
+// replaced.
+func (c *Catalog) Add(lang string, msgs map[string]string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.msgs[tag]; !ok {
+		c.msgs[tag] = make(map[string]string)
+		c.langs = append(c.langs, tag)
+	}
+	for k, v := range msgs {
+		c.msgs[tag][k] = v
+	}
+	return nil
+}
+
+// Languages returns the languages of the catalog. English always goes
+// first.
+func (c *Catalog) Languages() []language.Tag {
+	res := make([]language.Tag, len(c.langs))
+	copy(res, c.langs)
+	return res
+}
+
+// Localizer provides localized messages from a Catalog for one language.
+type Localizer struct {
+	cat  *Catalog
+	lang language.Tag
+}
+
+// LocalizerOption is a function that modifies Localizer settings.
+type LocalizerOption func(*Localizer)
+
+// OptLanguage sets the language of a Localizer. The language is a BCP 47
+// tag ("pt-BR") or a POSIX locale ("pt_BR.UTF-8"). If the language is
+// not given, the LANG environment variable is used.
+func OptLanguage(lang string) LocalizerOption {
+	return func(l *Localizer) {
+		l.lang = parseLang(lang)
+	}
+}
+
+// NewLocalizer creates a Localizer for the catalog. The language comes
+// from OptLanguage option or from the LANG environment variable. The
+// closest language of the catalog is used, English is the fallback.
+func NewLocalizer(cat *Catalog, opts ...LocalizerOption) *Localizer {
+	res := &Localizer{cat: cat, lang: parseLang(os.Getenv("LANG"))}
+	for _, opt := range opts {
+		opt(res)
+	}
+	matcher := language.NewMatcher(cat.langs)
+	_, idx, _ := matcher.Match(res.lang)
+	res.lang = cat.langs[idx]
+	return res
+}
+
+// Language returns the catalog language used by the Localizer.
+func (l *Localizer) Language() language.Tag {
+	return l.lang
+}
+
+// Template returns the template for the key. If the key is missing in
+// the Localizer language, parent languages are tried ("pt" for "pt-BR"),
+// and then English, including its regional variants like "en-US". If
+// there is no English template either, the key itself is used as
+// a template.
+func (l *Localizer) Template(key string) string {
+	for tag := l.lang; tag != language.Und; tag = tag.Parent() {
+		if res, ok := l.cat.msgs[tag][key]; ok {
+			return res
+		}
+	}
+	en, _ := language.English.Base()
+	for _, tag := range l.cat.langs {
+		if base, _ := tag.Base(); base != en {
+			continue
+		}
+		if res, ok := l.cat.msgs[tag][key]; ok {
+			return res
+		}
+	}
+	return key
+}
+
+// Message creates a MessageBase from the localized template of the key.
+func (l *Localizer) Message(key string, vars []any) MessageBase {
+	return NewMessage(l.Template(key), vars)
+}
+
+// FormatMessage formats the localized template of the key the same way
+// as the FormatMessage function does.
+func (l *Localizer) FormatMessage(key string, vars []any) string {
+	return FormatMessage(l.Template(key), vars)
+}
+
+// parseLang converts a BCP 47 tag or a POSIX locale to a language tag.
+// It returns language.Und if the language cannot be recognized.
+func parseLang(s string) language.Tag {
+	if i := strings.IndexAny(s, ".@"); i > -1 {
+		s = s[:i]
+	}
+	if s == "" || s == "C" || s == "POSIX" {
+		return language.Und
+	}
+	s = strings.ReplaceAll(s, "_", "-")
+	res, err := language.Parse(s)
+	if err != nil {
+		return language.Und
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+package gnlib_test
+
+import (
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func testCatalog(t *testing.T) *gnlib.Catalog {
+	cat := gnlib.NewCatalog()
+	err := cat.Add("en", map[string]string{
+		"found":  "Found <em>%d</em> names in <title>%s</title>",
+		"only":   "English only",
+		"nofile": "<warning>Cannot open %s</warning>",
+	})
+	assert.Nil(t, err)
+	err = cat.Add("pt-BR", map[string]string{
+		"found": "Encontrados <em>%d</em> nomes em <title>%s</title>",
+	})
+	assert.Nil(t, err)
+	err = cat.Add("de", map[string]string{
+		"found": "<em>%d</em> Namen in <title>%s</title> gefunden",
+	})
+	assert.Nil(t, err)
+	err = cat.Add("zh", map[string]string{
+		"found": "在<title>%[2]s</title>中找到<em>%[1]d</em>个名称",
+	})
+	assert.Nil(t, err)
+	return cat
+}
+
+func TestCatalogAdd(t *testing.T) {
+	assert := assert.New(t)
+	cat := testCatalog(t)
+	assert.Equal(
+		[]language.Tag{
+			language.English,
+			language.BrazilianPortuguese,
+			language.German,
+			language.Chinese,
+		},
+		cat.Languages(),
+	)
+	err := cat.Add("not a language!", nil)
+	assert.NotNil(err)
+}
+
+func TestLocalizer(t *testing.T) {
+	assert := assert.New(t)
+	cat := testCatalog(t)
+	vars := []any{5, "a.txt"}
+	tests := []struct {
+		msg, lang, res string
+	}{
+		{"en", "en", "Found 5 names in a.txt"},
+		{"pt", "pt-BR", "Encontrados 5 nomes em a.txt"},
+		{"pt posix", "pt_BR.UTF-8", "Encontrados 5 nomes em a.txt"},
+		{"pt closest", "pt", "Encontrados 5 nomes em a.txt"},
+		{"de", "de-DE", "5 Namen in a.txt gefunden"},
+		{"zh", "zh-CN", "在a.txt中找到5个名称"},
+		{"fallback", "fr", "Found 5 names in a.txt"},
+		{"bad", "???", "Found 5 names in a.txt"},
+	}
+
+	for _, v := range tests {
+		l := gnlib.NewLocalizer(cat, gnlib.OptLanguage(v.lang))
+		res := l.Message("found", vars).Render(gnlib.PlainRenderer{})
+		assert.Equal(v.res, res, v.msg)
+	}
+}
+
+func TestLocalizerTags(t *testing.T) {
+	assert := assert.New(t)
+	cat := testCatalog(t)
+	l := gnlib.NewLocalizer(cat, gnlib.OptLanguage("de"))
+	assert.Equal(language.German, l.Language())
+	res := l.FormatMessage("found", []any{5, "a.txt"})
+	assert.Equal("\033[33m5\033[0m Namen in \033[32ma.txt\033[0m gefunden", res)
+	assert.Equal("English only", l.Template("only"))
+	assert.Equal("missing key", l.Template("missing key"))
+}
+
+func TestLocalizerEnglishVariant(t *testing.T) {
+	assert := assert.New(t)
+	cat := gnlib.NewCatalog()
+	err := cat.Add("en-US", map[string]string{"k": "Key"})
+	assert.Nil(err)
+	err = cat.Add("pt", map[string]string{"p": "Chave"})
+	assert.Nil(err)
+
+	for _, lang := range []string{"fr", "en_GB", "en-US", "pt-BR"} {
+		l := gnlib.NewLocalizer(cat, gnlib.OptLanguage(lang))
+		assert.Equal("Key", l.Template("k"), lang)
+	}
+	l := gnlib.NewLocalizer(cat, gnlib.OptLanguage("pt-BR"))
+	assert.Equal("Chave", l.Template("p"))
+}
+
+func TestLocalizerEnv(t *testing.T) {
+	assert := assert.New(t)
+	cat := testCatalog(t)
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	l := gnlib.NewLocalizer(cat)
+	assert.Equal(language.German, l.Language())
+
+	l = gnlib.NewLocalizer(cat, gnlib.OptLanguage("pt-BR"))
+	assert.Equal(language.BrazilianPortuguese, l.Language())
+
+	t.Setenv("LANG", "C")
+	l = gnlib.NewLocalizer(cat)
+	assert.Equal(language.English, l.Language())
+}
@@ -0,0 +1,209 @@
+package gnlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Chunker groups items from an input channel into chunks. It never
+// blocks after its context is cancelled, and it reports items that were
+// received from the input, but not delivered to the output.
+//
+// Example:
+//
+//	ch := Chunker[string]{Size: 1000, MaxDelay: time.Second}
+//	chunks, errc := ch.Run(ctx, names)
+//	for chunk := range chunks { verify(chunk) }
+//	if err := <-errc; err != nil {
+//		var uErr *UndeliveredError[string]
+//		if errors.As(err, &uErr) { log(uErr.Items) }
+//	}
+type Chunker[T any] struct {
+	// Size is the maximum number of items in a chunk. If it is less than 1,
+	// chunks are limited by other settings only.
+	Size int
+
+	// Weight, if set, returns the cost of an item, for example its size
+	// in bytes. It is used together with MaxWeight.
+	Weight func(T) int
+
+	// MaxWeight is the maximum total weight of items in a chunk. A chunk
+	// is sent before an item that would exceed MaxWeight is added to it.
+	// An item that is heavier than MaxWeight makes its own chunk. If
+	// MaxWeight is not positive, or Weight is not set, chunks are not
+	// limited by weight.
+	MaxWeight int
+
+	// MaxDelay is the maximum time between receiving the first item of
+	// a chunk and sending the chunk. If it is not positive, chunks are
+	// sent only when they are full or when the input channel is closed.
+	MaxDelay time.Duration
+
+	// OnUndelivered, if set, is called with items that were received from
+	// the input channel, but were not delivered because the context was
+	// cancelled. Items that stay in the input channel are not included.
+	OnUndelivered func(items []T)
+}
+
+// UndeliveredError is returned by Chunker when its context is cancelled.
+// It contains items that were received from the input channel, but were
+// not sent to the output.
+type UndeliveredError[T any] struct {
+	// Items were received, but not delivered.
+	Items []T
+
+	// Err is the cause of the context cancellation.
+	Err error
+}
+
+// Error implements error interface.
+func (e *UndeliveredError[T]) Error() string {
+	return fmt.Sprintf("chunker stopped with %d undelivered items: %s",
+		len(e.Items), e.Err)
+}
+
+// Unwrap returns the cause of the cancellation.
+func (e *UndeliveredError[T]) Unwrap() error {
+	return e.Err
+}
+
+// Run starts reading the input channel and returns the output channel
+// with chunks and an error channel. The output channel is closed when
+// the input channel is closed and all chunks are sent, or when the
+// context is cancelled. After that the error channel receives nil
+// (it is closed) if all chunks were delivered, or UndeliveredError if
+// the cancellation stopped the chunker early.
+func (c Chunker[T]) Run(
+	ctx context.Context,
+	input <-chan T,
+) (<-chan []T, <-chan error) {
+	output := make(chan []T)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		chunk, done := c.run(ctx, input, output)
+		close(output)
+		if done {
+			return
+		}
+		if len(chunk) > 0 && c.OnUndelivered != nil {
+			c.OnUndelivered(chunk)
+		}
+		errc <- &UndeliveredError[T]{Items: chunk, Err: context.Cause(ctx)}
+	}()
+	return output, errc
+}
+
+// run does the chunking. It returns true if all items were delivered,
+// or undelivered items and false if it stopped because of the context.
+func (c Chunker[T]) run(
+	ctx context.Context,
+	input <-chan T,
+	output chan<- []T,
+) ([]T, bool) {
+	var chunk []T
+	var weight int
+	timer := time.NewTimer(c.MaxDelay)
+	timer.Stop()
+	defer timer.Stop()
+	var timeout <-chan time.Time // nil when there is no partial chunk
+
+	send := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case output <- chunk:
+		}
+		chunk = nil
+		weight = 0
+		timer.Stop()
+		timeout = nil
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return chunk, false
+		case <-timeout:
+			if !send() {
+				return chunk, false
+			}
+		case val, ok := <-input:
+			if !ok {
+				if len(chunk) > 0 && !send() {
+					return chunk, false
+				}
+				return nil, true
+			}
+			w := c.weight(val)
+			if len(chunk) > 0 && !c.fits(weight, w) && !send() {
+				return append(chunk, val), false
+			}
+			if len(chunk) == 0 && c.MaxDelay > 0 {
+				timer.Reset(c.MaxDelay)
+				timeout = timer.C
+			}
+			chunk = append(chunk, val)
+			weight += w
+			if c.full(len(chunk), weight) && !send() {
+				return chunk, false
+			}
+		}
+	}
+}
+
+// weight returns the weight of an item, or 0 if chunks are not limited
+// by weight.
+func (c Chunker[T]) weight(v T) int {
+	if c.Weight == nil || c.MaxWeight < 1 {
+		return 0
+	}
+	return c.Weight(v)
+}
+
+// fits returns true if an item with weight w can be added to a chunk
+// with the given weight.
+func (c Chunker[T]) fits(weight, w int) bool {
+	return c.MaxWeight < 1 || weight+w <= c.MaxWeight
+}
+
+// full returns true if a chunk with the given length and weight has to
+// be sent.
+func (c Chunker[T]) full(l, weight int) bool {
+	return l == c.Size || (c.MaxWeight > 0 && weight >= c.MaxWeight)
+}
+
+// Split divides a slice into chunks using the same limits as Run. The
+// MaxDelay and OnUndelivered settings are ignored. Chunks share memory
+// with the original slice.
+//
+// Example:
+//
+//	ch := Chunker[string]{
+//		Size:      1000,
+//		Weight:    func(s string) int { return len(s) },
+//		MaxWeight: 1 << 20,
+//	}
+//	for _, batch := range ch.Split(names) { verify(batch) }
+func (c Chunker[T]) Split(s []T) [][]T {
+	var res [][]T
+	var start, weight int
+	for i, v := range s {
+		w := c.weight(v)
+		if i > start && !c.fits(weight, w) {
+			res = append(res, s[start:i:i])
+			start, weight = i, 0
+		}
+		weight += w
+		if c.full(i-start+1, weight) {
+			res = append(res, s[start:i+1:i+1])
+			start, weight = i+1, 0
+		}
+	}
+	if start < len(s) {
+		res = append(res, s[start:len(s):len(s)])
+	}
+	return res
+}
@@ -0,0 +1,260 @@
+package gnlib_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertNoLeak checks that the number of goroutines returns to the given
+// value.
+func assertNoLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			n := runtime.Stack(buf, true)
+			t.Fatalf("goroutines leaked: %d > %d\n%s",
+				runtime.NumGoroutine(), before, buf[:n])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	assert := assert.New(t)
+	input := make(chan int)
+	go func() {
+		for i := 1; i <= 5; i++ {
+			input <- i
+		}
+		close(input)
+	}()
+
+	chunks, errc := gnlib.Chunker[int]{Size: 2}.Run(context.Background(), input)
+	var res [][]int
+	for chunk := range chunks {
+		res = append(res, chunk)
+	}
+	assert.Equal([][]int{{1, 2}, {3, 4}, {5}}, res)
+	assert.Nil(<-errc)
+}
+
+func TestChunkerCancelAfterDone(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		input <- i
+	}
+	close(input)
+
+	chunks, errc := gnlib.Chunker[int]{Size: 2}.Run(ctx, input)
+	var res [][]int
+	for chunk := range chunks {
+		res = append(res, chunk)
+	}
+	// all chunks are delivered, a late cancellation is not an error
+	cancel()
+	assert.Equal([][]int{{1, 2}, {3}}, res)
+	assert.Nil(<-errc)
+}
+
+func TestChunkerCancelOnSend(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan int, 10)
+	for i := range 5 {
+		input <- i
+	}
+
+	var undelivered []int
+	ch := gnlib.Chunker[int]{
+		Size:          2,
+		OnUndelivered: func(items []int) { undelivered = items },
+	}
+	chunks, errc := ch.Run(ctx, input)
+	assert.Equal([]int{0, 1}, <-chunks)
+
+	// consumer stops reading, the chunker is blocked on sending [2 3]
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	err := <-errc
+	assert.ErrorIs(err, context.Canceled)
+	var uErr *gnlib.UndeliveredError[int]
+	assert.True(errors.As(err, &uErr))
+	assert.Equal([]int{2, 3}, uErr.Items)
+	assert.Equal([]int{2, 3}, undelivered)
+	_, ok := <-chunks
+	assert.False(ok)
+	assertNoLeak(t, before)
+}
+
+func TestChunkerCancelOnReceive(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	input := make(chan int)
+	chunks, errc := gnlib.Chunker[int]{Size: 3}.Run(ctx, input)
+	input <- 1
+	input <- 2
+	errStop := errors.New("stop")
+	cancel(errStop)
+
+	for range chunks {
+		assert.Fail("no chunks expected")
+	}
+	err := <-errc
+	assert.ErrorIs(err, errStop)
+	var uErr *gnlib.UndeliveredError[int]
+	assert.ErrorAs(err, &uErr)
+	assert.Equal([]int{1, 2}, uErr.Items)
+	assert.Equal("chunker stopped with 2 undelivered items: stop", err.Error())
+	assertNoLeak(t, before)
+}
+
+func TestChunkerCancelEmpty(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	var called bool
+	ch := gnlib.Chunker[int]{
+		Size:          3,
+		OnUndelivered: func([]int) { called = true },
+	}
+	_, errc := ch.Run(ctx, make(chan int))
+	cancel()
+	err := <-errc
+	var uErr *gnlib.UndeliveredError[int]
+	assert.ErrorAs(err, &uErr)
+	assert.Empty(uErr.Items)
+	assert.False(called)
+	assertNoLeak(t, before)
+}
+
+func TestChunkChannelNoLeak(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan int, 10)
+	for i := range 10 {
+		input <- i
+	}
+	output := gnlib.ChunkChannel(ctx, input, 2)
+	assert.Equal([]int{0, 1}, <-output)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assertNoLeak(t, before)
+
+	before = runtime.NumGoroutine()
+	ctx, cancel = context.WithCancel(context.Background())
+	output = gnlib.ChunkChannelTimeout(ctx, input, 100, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assertNoLeak(t, before)
+	_, ok := <-output
+	assert.False(ok)
+}
+
+func TestChunkerSplit(t *testing.T) {
+	assert := assert.New(t)
+	strLen := func(s string) int { return len(s) }
+	input := []string{"aa", "bbb", "c", "dddddd", "ee", "f", "g"}
+	tests := []struct {
+		msg string
+		ch  gnlib.Chunker[string]
+		res [][]string
+	}{
+		{
+			"size only",
+			gnlib.Chunker[string]{Size: 3},
+			[][]string{{"aa", "bbb", "c"}, {"dddddd", "ee", "f"}, {"g"}},
+		},
+		{
+			"weight only",
+			gnlib.Chunker[string]{Weight: strLen, MaxWeight: 5},
+			[][]string{{"aa", "bbb"}, {"c"}, {"dddddd"}, {"ee", "f", "g"}},
+		},
+		{
+			"size and weight",
+			gnlib.Chunker[string]{Size: 2, Weight: strLen, MaxWeight: 5},
+			[][]string{{"aa", "bbb"}, {"c"}, {"dddddd"}, {"ee", "f"}, {"g"}},
+		},
+		{
+			"weight without func",
+			gnlib.Chunker[string]{Size: 4, MaxWeight: 1},
+			[][]string{{"aa", "bbb", "c", "dddddd"}, {"ee", "f", "g"}},
+		},
+		{
+			"no limits",
+			gnlib.Chunker[string]{},
+			[][]string{input},
+		},
+	}
+
+	for _, v := range tests {
+		res := v.ch.Split(input)
+		assert.Equal(v.res, res, v.msg)
+	}
+	assert.Nil(gnlib.Chunker[string]{Size: 2}.Split(nil))
+
+	// chunks do not overwrite each other on append
+	res := gnlib.Chunker[string]{Size: 2}.Split(input)
+	res[0] = append(res[0], "x")
+	assert.Equal([]string{"c", "dddddd"}, res[1])
+}
+
+func TestChunkerWeight(t *testing.T) {
+	assert := assert.New(t)
+	input := make(chan string)
+	go func() {
+		for _, v := range []string{"aa", "bbb", "c", "dddddd", "ee", "f", "g"} {
+			input <- v
+		}
+		close(input)
+	}()
+	ch := gnlib.Chunker[string]{
+		Size:      2,
+		Weight:    func(s string) int { return len(s) },
+		MaxWeight: 5,
+	}
+	chunks, errc := ch.Run(context.Background(), input)
+	var res [][]string
+	for chunk := range chunks {
+		res = append(res, chunk)
+	}
+	assert.Equal(
+		[][]string{{"aa", "bbb"}, {"c"}, {"dddddd"}, {"ee", "f"}, {"g"}},
+		res,
+	)
+	assert.Nil(<-errc)
+}
+
+func TestChunkerWeightCancel(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan string, 2)
+	input <- "aaa"
+	input <- "bbb"
+	ch := gnlib.Chunker[string]{
+		Weight:    func(s string) int { return len(s) },
+		MaxWeight: 5,
+	}
+	_, errc := ch.Run(ctx, input)
+	// "bbb" does not fit, the chunker is blocked on sending ["aaa"]
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	var uErr *gnlib.UndeliveredError[string]
+	assert.ErrorAs(<-errc, &uErr)
+	assert.Equal([]string{"aaa", "bbb"}, uErr.Items)
+	assertNoLeak(t, before)
+}
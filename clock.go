@@ -0,0 +1,22 @@
+package gnlib
+
+import "time"
+
+// Clock provides the current time and timers. It allows to replace the
+// real time with a fake one in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time after
+	// the duration d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock that uses the real time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
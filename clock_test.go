@@ -0,0 +1,80 @@
+package gnlib_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a Clock that moves only when Advance is called.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires expired timers.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	var rest []fakeTimer
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			rest = append(rest, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = rest
+}
+
+// WaitTimers blocks until there are n pending timers.
+func (c *fakeClock) WaitTimers(n int) {
+	for {
+		c.mu.Lock()
+		l := len(c.timers)
+		c.mu.Unlock()
+		if l >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	assert := assert.New(t)
+	start := gnlib.SystemClock.Now()
+	<-gnlib.SystemClock.After(time.Millisecond)
+	assert.GreaterOrEqual(time.Since(start), time.Millisecond)
+}
@@ -0,0 +1,264 @@
+package gnlib
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraint is a version requirement, for example ">= v1.1.0, < v2".
+//
+// A constraint consists of alternatives separated by "||", and every
+// alternative is a comma-separated list of conditions that all must be
+// true. A condition is a version with an operator:
+//
+//	=  v1.2.3   exactly v1.2.3, "= v1.2" means any v1.2.x
+//	!= v1.2.3   anything but v1.2.3
+//	>  v1.2.3   newer than v1.2.3, "> v1.2" means v1.3.0 or newer
+//	>= v1.2.3   v1.2.3 or newer
+//	<  v1.2.3   older than v1.2.3
+//	<= v1.2.3   v1.2.3 or older, "<= v1.2" means any v1.2.x or older
+//	~  v1.2.3   patch updates, >= v1.2.3, < v1.3.0
+//	^  v1.2.3   compatible updates, >= v1.2.3, < v2.0.0
+//
+// A version without an operator means "=". Versions can omit minor and
+// patch numbers. For "^" the first non-zero number cannot change, so
+// "^v0.2.3" means ">= v0.2.3, < v0.3.0".
+//
+// Pre-release versions satisfy an alternative only if one of its
+// conditions has a pre-release of the same major, minor and patch
+// numbers. So "< v2" does not match v2.0.0-beta.1, and
+// ">= v1.2.0-beta.1" matches v1.2.0-beta.2, but not v1.3.0-beta.1.
+type Constraint struct {
+	src    string
+	groups [][]condition
+}
+
+type condition struct {
+	op string
+	v  Version
+}
+
+// ConstraintError is returned when a string cannot be parsed as
+// a Constraint.
+type ConstraintError struct {
+	// Input is the string that failed to parse.
+	Input string
+
+	// Reason describes what is wrong with the input.
+	Reason string
+}
+
+// Error implements error interface.
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("invalid constraint %q: %s", e.Input, e.Reason)
+}
+
+// operators are sorted so that longer operators are matched first.
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a version constraint. It returns
+// *ConstraintError if the string is not a valid constraint.
+//
+// Example:
+//
+//	c, err := ParseConstraint(">= v1.1.0, < v2 || ^v3.2")
+//	if err != nil { return err }
+//	ok := c.Check(MustParseVersion("v1.5.0")) // true
+func ParseConstraint(s string) (*Constraint, error) {
+	res := &Constraint{src: strings.TrimSpace(s)}
+	for alt := range strings.SplitSeq(s, "||") {
+		var group []condition
+		for cond := range strings.SplitSeq(alt, ",") {
+			conds, err := parseCondition(strings.TrimSpace(cond))
+			if err != nil {
+				return nil, &ConstraintError{Input: s, Reason: err.Error()}
+			}
+			group = append(group, conds...)
+		}
+		res.groups = append(res.groups, group)
+	}
+	return res, nil
+}
+
+// MustParseConstraint is like ParseConstraint, but panics if the string
+// cannot be parsed. It is meant for constants known to be valid.
+func MustParseConstraint(s string) *Constraint {
+	res, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// String returns the constraint as it was given.
+func (c *Constraint) String() string {
+	return c.src
+}
+
+// Check returns true if the version satisfies the constraint.
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		if checkGroup(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (c *Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.src), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (c *Constraint) UnmarshalText(bs []byte) error {
+	res, err := ParseConstraint(string(bs))
+	if err != nil {
+		return err
+	}
+	*c = *res
+	return nil
+}
+
+// SortVersions sorts versions in ascending order of precedence. Versions
+// with the same precedence keep their order.
+func SortVersions(vs []Version) {
+	slices.SortStableFunc(vs, Version.Compare)
+}
+
+// MaxSatisfying returns the newest version that satisfies the constraint.
+// It returns false if none of the versions satisfies it.
+//
+// Example:
+//
+//	dumps := []Version{MustParseVersion("v1.0.3"), MustParseVersion("v2.0.0")}
+//	v, ok := MaxSatisfying(dumps, MustParseConstraint("^v1"))
+//	// v1.0.3, true
+func MaxSatisfying(vs []Version, c *Constraint) (Version, bool) {
+	var res Version
+	var found bool
+	for _, v := range vs {
+		if !c.Check(v) {
+			continue
+		}
+		if !found || res.Less(v) {
+			res = v
+			found = true
+		}
+	}
+	return res, found
+}
+
+func checkGroup(group []condition, v Version) bool {
+	for _, cond := range group {
+		if !cond.check(v) {
+			return false
+		}
+	}
+	if !v.IsPrerelease() {
+		return true
+	}
+	for _, cond := range group {
+		cv := cond.v
+		if cv.IsPrerelease() && cv.Major == v.Major &&
+			cv.Minor == v.Minor && cv.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c condition) check(v Version) bool {
+	res := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return res == 0
+	case "!=":
+		return res != 0
+	case ">":
+		return res > 0
+	case ">=":
+		return res >= 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	}
+	return false
+}
+
+// parseCondition converts a condition to one or two simple conditions
+// that use only comparison operators.
+func parseCondition(s string) ([]condition, error) {
+	if s == "" {
+		return nil, errors.New("empty condition")
+	}
+	op := "="
+	for _, o := range operators {
+		if rest, ok := strings.CutPrefix(s, o); ok {
+			op = o
+			s = strings.TrimSpace(rest)
+			break
+		}
+	}
+	v, n, err := ParsePartialVersion(s)
+	if err != nil {
+		var vErr *VersionError
+		if errors.As(err, &vErr) {
+			return nil, fmt.Errorf("version %q: %s", vErr.Input, vErr.Reason)
+		}
+		return nil, err
+	}
+
+	switch op {
+	case "=":
+		if n == 3 {
+			return []condition{{"=", v}}, nil
+		}
+		return []condition{{">=", v}, {"<", bump(v, n)}}, nil
+	case "!=":
+		if n < 3 {
+			return nil, fmt.Errorf("%q needs a full version", op+s)
+		}
+		return []condition{{"!=", v}}, nil
+	case ">":
+		if n == 3 {
+			return []condition{{">", v}}, nil
+		}
+		return []condition{{">=", bump(v, n)}}, nil
+	case "<=":
+		if n == 3 {
+			return []condition{{"<=", v}}, nil
+		}
+		return []condition{{"<", bump(v, n)}}, nil
+	case "~":
+		return []condition{{">=", v}, {"<", bump(v, min(n, 2))}}, nil
+	case "^":
+		var pos int
+		switch {
+		case v.Major > 0 || n == 1:
+			pos = 1
+		case v.Minor > 0 || n == 2:
+			pos = 2
+		default:
+			pos = 3
+		}
+		return []condition{{">=", v}, {"<", bump(v, pos)}}, nil
+	}
+	// ">=" and "<"
+	return []condition{{op, v}}, nil
+}
+
+// bump returns the smallest version that is larger than all versions
+// that share the first n numbers with v.
+func bump(v Version, n int) Version {
+	switch n {
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
@@ -0,0 +1,164 @@
+package gnlib_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		constr  string
+		matches []string
+		misses  []string
+	}{
+		{
+			">= v1.1.0, < v2",
+			[]string{"v1.1.0", "v1.9.9", "1.1.0+build"},
+			[]string{"v1.0.9", "v2.0.0", "v2.0.0-beta.1", "v1.5.0-rc.1"},
+		},
+		{"v1.2.3", []string{"v1.2.3", "v1.2.3+meta"}, []string{"v1.2.4", "v1.2.3-rc.1"}},
+		{"= v1.2", []string{"v1.2.0", "v1.2.99"}, []string{"v1.1.9", "v1.3.0"}},
+		{"!= v1.2.3", []string{"v1.2.2", "v1.2.4"}, []string{"v1.2.3"}},
+		{"> v1.2.3", []string{"v1.2.4"}, []string{"v1.2.3", "v1.0.0"}},
+		{"> v1.2", []string{"v1.3.0"}, []string{"v1.2.9"}},
+		{"<= v1.2", []string{"v1.2.9", "v0.1.0"}, []string{"v1.3.0"}},
+		{"<= v1.2.3", []string{"v1.2.3"}, []string{"v1.2.4"}},
+		{"~v1.2.3", []string{"v1.2.3", "v1.2.10"}, []string{"v1.2.2", "v1.3.0"}},
+		{"~1.2", []string{"v1.2.0", "v1.2.10"}, []string{"v1.3.0"}},
+		{"~1", []string{"v1.0.0", "v1.9.0"}, []string{"v2.0.0"}},
+		{"^v1.2.3", []string{"v1.2.3", "v1.9.0"}, []string{"v1.2.2", "v2.0.0"}},
+		{"^0.2.3", []string{"v0.2.3", "v0.2.9"}, []string{"v0.3.0", "v0.2.2"}},
+		{"^0.0.3", []string{"v0.0.3"}, []string{"v0.0.4", "v0.0.2"}},
+		{"^0.0", []string{"v0.0.0", "v0.0.9"}, []string{"v0.1.0"}},
+		{"^0", []string{"v0.0.0", "v0.9.0"}, []string{"v1.0.0"}},
+		{
+			"^v1.2 || ~v3.1.0, != v3.1.2",
+			[]string{"v1.5.0", "v3.1.1", "v3.1.3"},
+			[]string{"v2.0.0", "v3.1.2", "v3.2.0"},
+		},
+		{
+			">= v1.2.0-beta.1",
+			[]string{"v1.2.0-beta.1", "v1.2.0-beta.2", "v1.2.0", "v3.0.0"},
+			[]string{"v1.2.0-alpha", "v1.3.0-beta.1"},
+		},
+		{
+			"^v1.2.3-rc.1",
+			[]string{"v1.2.3-rc.2", "v1.4.0"},
+			[]string{"v1.2.4-rc.1", "v2.0.0-rc.1"},
+		},
+	}
+
+	for _, v := range tests {
+		c, err := gnlib.ParseConstraint(v.constr)
+		assert.Nil(err, v.constr)
+		for _, ver := range v.matches {
+			assert.True(c.Check(gnlib.MustParseVersion(ver)), v.constr+" "+ver)
+		}
+		for _, ver := range v.misses {
+			assert.False(c.Check(gnlib.MustParseVersion(ver)), v.constr+" "+ver)
+		}
+	}
+}
+
+func TestParseConstraintErr(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, input, err string
+	}{
+		{"empty", "", `invalid constraint "": empty condition`},
+		{"empty and", ">= v1,", `invalid constraint ">= v1,": empty condition`},
+		{"empty or", "v1 ||", `invalid constraint "v1 ||": empty condition`},
+		{
+			"bad version", ">= v1.x",
+			`invalid constraint ">= v1.x": version "v1.x": "x" is not a number`,
+		},
+		{
+			"partial pre", "^v1-beta",
+			`invalid constraint "^v1-beta": version "v1-beta": pre-release or build needs all three numbers`,
+		},
+		{
+			"partial not", "!= v1.2",
+			`invalid constraint "!= v1.2": "!=v1.2" needs a full version`,
+		},
+		{
+			"unknown op", "=> v1",
+			`invalid constraint "=> v1": version "> v1": "> v1" is not a number`,
+		},
+	}
+
+	for _, v := range tests {
+		_, err := gnlib.ParseConstraint(v.input)
+		var cErr *gnlib.ConstraintError
+		assert.ErrorAs(err, &cErr, v.msg)
+		assert.Equal(v.err, err.Error(), v.msg)
+	}
+	assert.Panics(func() { gnlib.MustParseConstraint("~") })
+}
+
+func TestConstraintJSON(t *testing.T) {
+	assert := assert.New(t)
+	type req struct {
+		Needs *gnlib.Constraint `json:"needs"`
+	}
+	var r req
+	err := json.Unmarshal([]byte(`{"needs":">= v1.1.0, < v2"}`), &r)
+	assert.Nil(err)
+	assert.Equal(">= v1.1.0, < v2", r.Needs.String())
+	assert.True(r.Needs.Check(gnlib.MustParseVersion("v1.2.0")))
+
+	bs, err := json.Marshal(r)
+	assert.Nil(err)
+	var r2 req
+	err = json.Unmarshal(bs, &r2)
+	assert.Nil(err)
+	assert.Equal(r.Needs.String(), r2.Needs.String())
+
+	err = json.Unmarshal([]byte(`{"needs":">= v1.x"}`), &r)
+	var cErr *gnlib.ConstraintError
+	assert.ErrorAs(err, &cErr)
+}
+
+func TestSortVersions(t *testing.T) {
+	assert := assert.New(t)
+	vs := gnlib.Map(
+		[]string{"v1.10.0", "v1.2.0", "v1.2.0-rc.1", "v0.9.0", "v1.2.0+b", "v2.0.0"},
+		gnlib.MustParseVersion,
+	)
+	gnlib.SortVersions(vs)
+	res := gnlib.Map(vs, gnlib.Version.String)
+	assert.Equal(
+		[]string{"v0.9.0", "v1.2.0-rc.1", "v1.2.0", "v1.2.0+b", "v1.10.0", "v2.0.0"},
+		res,
+	)
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	assert := assert.New(t)
+	dumps := gnlib.Map(
+		[]string{"v1.0.3", "v1.4.0", "v1.5.0-beta.1", "v2.0.0", "v1.3.9"},
+		gnlib.MustParseVersion,
+	)
+	tests := []struct {
+		constr, res string
+		ok          bool
+	}{
+		{"^v1", "v1.4.0", true},
+		{">= v1.1.0, < v2", "v1.4.0", true},
+		{"~v1.3", "v1.3.9", true},
+		{">= v1.5.0-beta.1, < v2", "v1.5.0-beta.1", true},
+		{">= v1", "v2.0.0", true},
+		{"^v3", "", false},
+	}
+
+	for _, v := range tests {
+		res, ok := gnlib.MaxSatisfying(dumps, gnlib.MustParseConstraint(v.constr))
+		assert.Equal(v.ok, ok, v.constr)
+		if ok {
+			assert.Equal(v.res, res.String(), v.constr)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package gnlib
+
+import (
+	"iter"
+	"slices"
+)
+
+// Counter counts occurrences of comparable values. It is useful for
+// frequency statistics, for example for finding the most prevalent
+// kingdom among verified names. Values with equal counts are ordered by
+// their first occurrence, so results are deterministic.
+//
+// Counter must be created with NewCounter. It is not safe for concurrent
+// use.
+type Counter[T comparable] struct {
+	counts map[T]int
+	order  []T
+	total  int
+}
+
+// CountItem is a value with its count and its fraction of the total.
+type CountItem[T comparable] struct {
+	// Item is the counted value.
+	Item T
+
+	// Count is the number of occurrences of Item.
+	Count int
+
+	// Fraction is Count divided by the total of all counts.
+	Fraction float64
+}
+
+// NewCounter creates a Counter and counts the given values.
+func NewCounter[T comparable](vals ...T) *Counter[T] {
+	res := &Counter[T]{counts: make(map[T]int)}
+	for _, v := range vals {
+		res.Add(v)
+	}
+	return res
+}
+
+// Add counts one occurrence of the value.
+func (c *Counter[T]) Add(v T) {
+	c.AddN(v, 1)
+}
+
+// AddN counts n occurrences of the value. Calls with n less than 1 are
+// ignored.
+func (c *Counter[T]) AddN(v T, n int) {
+	if n < 1 {
+		return
+	}
+	if _, ok := c.counts[v]; !ok {
+		c.order = append(c.order, v)
+	}
+	c.counts[v] += n
+	c.total += n
+}
+
+// Count returns the number of occurrences of the value.
+func (c *Counter[T]) Count(v T) int {
+	return c.counts[v]
+}
+
+// Len returns the number of distinct values.
+func (c *Counter[T]) Len() int {
+	return len(c.order)
+}
+
+// Total returns the sum of all counts.
+func (c *Counter[T]) Total() int {
+	return c.total
+}
+
+// Merge adds counts of another counter. Values that are new to c are
+// appended in the order of their first occurrence in other.
+func (c *Counter[T]) Merge(other *Counter[T]) {
+	for _, v := range other.order {
+		c.AddN(v, other.counts[v])
+	}
+}
+
+// MostCommon returns up to n values with the highest counts in
+// descending order. Values with equal counts keep the order of their
+// first occurrence. If n is less than 1, all values are returned.
+func (c *Counter[T]) MostCommon(n int) []CountItem[T] {
+	res := make([]CountItem[T], len(c.order))
+	for i, v := range c.order {
+		res[i] = c.item(v)
+	}
+	slices.SortStableFunc(res, func(a, b CountItem[T]) int {
+		return b.Count - a.Count
+	})
+	if n > 0 && n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
+
+// Fractions returns the fraction of the total for every value. The
+// fractions are from 0 to 1 and their sum is 1.
+func (c *Counter[T]) Fractions() map[T]float64 {
+	res := make(map[T]float64, len(c.counts))
+	for _, v := range c.order {
+		res[v] = c.item(v).Fraction
+	}
+	return res
+}
+
+// Percentages returns the percentage of the total for every value. The
+// percentages are from 0 to 100.
+func (c *Counter[T]) Percentages() map[T]float64 {
+	res := c.Fractions()
+	for k := range res {
+		res[k] *= 100
+	}
+	return res
+}
+
+// All returns an iterator over values and their counts in the order of
+// the first occurrence of the values.
+func (c *Counter[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for _, v := range c.order {
+			if !yield(v, c.counts[v]) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Counter[T]) item(v T) CountItem[T] {
+	res := CountItem[T]{Item: v, Count: c.counts[v]}
+	if c.total > 0 {
+		res.Fraction = float64(res.Count) / float64(c.total)
+	}
+	return res
+}
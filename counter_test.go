@@ -0,0 +1,82 @@
+package gnlib_test
+
+import (
+	"testing"
+
+	"github.com/gnames/gnlib"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	assert := assert.New(t)
+	c := gnlib.NewCounter("Plantae", "Animalia", "Plantae")
+	c.Add("Fungi")
+	c.AddN("Animalia", 2)
+	c.AddN("Bacteria", 0)
+	c.AddN("Bacteria", -3)
+
+	assert.Equal(3, c.Len())
+	assert.Equal(6, c.Total())
+	assert.Equal(3, c.Count("Animalia"))
+	assert.Equal(2, c.Count("Plantae"))
+	assert.Equal(0, c.Count("Bacteria"))
+
+	var keys []string
+	for k, v := range c.All() {
+		keys = append(keys, k)
+		assert.Equal(c.Count(k), v)
+	}
+	assert.Equal([]string{"Plantae", "Animalia", "Fungi"}, keys)
+}
+
+func TestCounter_MostCommon(t *testing.T) {
+	assert := assert.New(t)
+	c := gnlib.NewCounter("b", "a", "c", "a", "b", "d")
+	tests := []struct {
+		msg string
+		n   int
+		res []string
+	}{
+		{"all", 0, []string{"b", "a", "c", "d"}},
+		{"negative", -1, []string{"b", "a", "c", "d"}},
+		{"top 1", 1, []string{"b"}},
+		{"top 3", 3, []string{"b", "a", "c"}},
+		{"too many", 10, []string{"b", "a", "c", "d"}},
+	}
+
+	for _, v := range tests {
+		items := c.MostCommon(v.n)
+		res := gnlib.Map(items, func(i gnlib.CountItem[string]) string {
+			return i.Item
+		})
+		assert.Equal(v.res, res, v.msg)
+	}
+
+	top := c.MostCommon(1)[0]
+	assert.Equal(2, top.Count)
+	assert.InDelta(1.0/3, top.Fraction, 1e-9)
+	assert.Empty(gnlib.NewCounter[int]().MostCommon(3))
+}
+
+func TestCounter_Percentages(t *testing.T) {
+	assert := assert.New(t)
+	c := gnlib.NewCounter(1, 1, 1, 2)
+	assert.Equal(map[int]float64{1: 0.75, 2: 0.25}, c.Fractions())
+	assert.Equal(map[int]float64{1: 75, 2: 25}, c.Percentages())
+	assert.Empty(gnlib.NewCounter[int]().Percentages())
+}
+
+func TestCounter_Merge(t *testing.T) {
+	assert := assert.New(t)
+	c1 := gnlib.NewCounter(vlib.Exact, vlib.Fuzzy)
+	c2 := gnlib.NewCounter(vlib.NoMatch, vlib.Fuzzy, vlib.NoMatch)
+	c1.Merge(c2)
+	assert.Equal(5, c1.Total())
+	assert.Equal(2, c1.Count(vlib.Fuzzy))
+	res := gnlib.Map(c1.MostCommon(0), func(i gnlib.CountItem[vlib.MatchTypeValue]) string {
+		return i.Item.String()
+	})
+	assert.Equal([]string{"Fuzzy", "NoMatch", "Exact"}, res)
+	assert.Equal(3, c2.Total())
+}
@@ -0,0 +1,235 @@
+// Package gnerr provides error codes shared by Global Names services.
+// The codes allow clients to branch on the type of a failure instead of
+// parsing free-form error messages.
+package gnerr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gnames/gnlib"
+)
+
+// Code is a type of a failure reported by a Global Names service.
+type Code int
+
+// Constants for error codes. The numeric values are not stable, use
+// their string IDs for storage and communication.
+const (
+	// NoError is the zero value of Code, it means that there was no
+	// failure. It is omitted from JSON fields with omitempty option.
+	NoError Code = iota
+
+	// Unknown is a failure that does not fit any other code.
+	Unknown
+
+	// InvalidInput means that a request is malformed or its options
+	// contain wrong values.
+	InvalidInput
+
+	// TooManyNames means that a request contains more names than
+	// a service is able to process at once.
+	TooManyNames
+
+	// ParseFailure means that a name-string could not be parsed.
+	ParseFailure
+
+	// DataSourceUnknown means that a request refers to a data-source ID
+	// that does not exist.
+	DataSourceUnknown
+
+	// NotFound means that a requested resource does not exist.
+	NotFound
+
+	// Timeout means that the processing took longer than allowed.
+	Timeout
+
+	// Unavailable means that a service or one of its dependencies
+	// is temporarily not available.
+	Unavailable
+
+	// Internal means a bug in a service.
+	Internal
+
+	// IncompatibleVersion means that versions of a client and a server
+	// cannot work together. Either side might be outdated, so it maps to
+	// 409 Conflict.
+	IncompatibleVersion
+)
+
+var codeToString = map[Code]string{
+	NoError:             "NO_ERROR",
+	Unknown:             "UNKNOWN",
+	InvalidInput:        "INVALID_INPUT",
+	TooManyNames:        "TOO_MANY_NAMES",
+	ParseFailure:        "PARSE_FAILURE",
+	DataSourceUnknown:   "DATA_SOURCE_UNKNOWN",
+	NotFound:            "NOT_FOUND",
+	Timeout:             "TIMEOUT",
+	Unavailable:         "UNAVAILABLE",
+	Internal:            "INTERNAL",
+	IncompatibleVersion: "INCOMPATIBLE_VERSION",
+}
+
+var stringToCode = func() map[string]Code {
+	res := make(map[string]Code)
+	for k, v := range codeToString {
+		res[v] = k
+	}
+	return res
+}()
+
+var codeToStatus = map[Code]int{
+	NoError:             http.StatusOK,
+	Unknown:             http.StatusInternalServerError,
+	InvalidInput:        http.StatusBadRequest,
+	TooManyNames:        http.StatusRequestEntityTooLarge,
+	ParseFailure:        http.StatusUnprocessableEntity,
+	DataSourceUnknown:   http.StatusBadRequest,
+	NotFound:            http.StatusNotFound,
+	Timeout:             http.StatusGatewayTimeout,
+	Unavailable:         http.StatusServiceUnavailable,
+	Internal:            http.StatusInternalServerError,
+	IncompatibleVersion: http.StatusConflict,
+}
+
+var codeToMessage = map[Code]string{
+	NoError:             "No error",
+	Unknown:             "<warning>Unknown error</warning>",
+	InvalidInput:        "<warning>Invalid input</warning>",
+	TooManyNames:        "<warning>Too many names in the request</warning>",
+	ParseFailure:        "<warning>Cannot parse the name-string</warning>",
+	DataSourceUnknown:   "<warning>Unknown data-source</warning>",
+	NotFound:            "<warning>Not found</warning>",
+	Timeout:             "<warning>The request timed out</warning>",
+	Unavailable:         "<warning>Service is temporarily unavailable</warning>",
+	Internal:            "<warning>Internal service error</warning>",
+	IncompatibleVersion: "<warning>Client and server versions are not compatible</warning>",
+}
+
+// New converts a string ID (case insensitive) to Code. If the ID is
+// unknown, it returns Unknown.
+func New(s string) Code {
+	if res, ok := stringToCode[strings.ToUpper(s)]; ok {
+		return res
+	}
+	return Unknown
+}
+
+// ID returns a stable string identifier of the code, for example
+// "TOO_MANY_NAMES".
+func (c Code) ID() string {
+	if res, ok := codeToString[c]; ok {
+		return res
+	}
+	return codeToString[Unknown]
+}
+
+// String implements fmt.Stringer interface and returns the ID of the code.
+func (c Code) String() string {
+	return c.ID()
+}
+
+// HTTPStatus returns the HTTP status code that corresponds to the code.
+func (c Code) HTTPStatus() int {
+	if res, ok := codeToStatus[c]; ok {
+		return res
+	}
+	return http.StatusInternalServerError
+}
+
+// Message returns the default user message of the code.
+func (c Code) Message() string {
+	if res, ok := codeToMessage[c]; ok {
+		return res
+	}
+	return codeToMessage[Unknown]
+}
+
+// MarshalJSON implements json.Marshaller interface and converts Code
+// into its string ID.
+func (c Code) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + c.ID() + "\""), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaller interface and converts
+// a string ID (case insensitive) into Code.
+func (c *Code) UnmarshalJSON(bs []byte) error {
+	var err error
+	var ok bool
+	s := strings.ToUpper(strings.Trim(string(bs), `"`))
+	*c, ok = stringToCode[s]
+	if !ok {
+		err = errors.New("cannot decode as an error Code")
+	}
+	return err
+}
+
+// Error is an error with a code. It implements gnlib.Error interface,
+// so it provides a user-friendly message as well.
+type Error struct {
+	// Code is the type of the failure.
+	Code Code
+
+	// Err is the underlying error, it can be nil.
+	Err error
+
+	gnlib.MessageBase
+}
+
+// NewError creates an Error with the given code and underlying error.
+// If msg is empty, the default message of the code is used.
+func NewError(code Code, err error, msg string, vars []any) *Error {
+	if msg == "" {
+		msg = code.Message()
+	}
+	return &Error{
+		Code:        code,
+		Err:         err,
+		MessageBase: gnlib.NewMessage(msg, vars),
+	}
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Code.ID()
+	}
+	return e.Code.ID() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON implements json.Marshaller interface. The message is
+// rendered as plain text.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	res := struct {
+		Code    Code   `json:"code"`
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}{
+		Code:    e.Code,
+		Status:  e.Code.HTTPStatus(),
+		Message: e.Render(gnlib.PlainRenderer{}),
+	}
+	return json.Marshal(res)
+}
+
+// CodeOf finds the first Error in the chain of err and returns its code.
+// It returns NoError if err is nil, and Unknown if there is no Error in
+// the chain.
+func CodeOf(err error) Code {
+	if err == nil {
+		return NoError
+	}
+	var gnErr *Error
+	if errors.As(err, &gnErr) {
+		return gnErr.Code
+	}
+	return Unknown
+}
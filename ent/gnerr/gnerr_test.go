@@ -0,0 +1,128 @@
+package gnerr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnlib"
+	"github.com/gnames/gnlib/ent/gnerr"
+	"github.com/gnames/gnlib/ent/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, inp string
+		out      gnerr.Code
+	}{
+		{"bad", "something", gnerr.Unknown},
+		{"too many", "TOO_MANY_NAMES", gnerr.TooManyNames},
+		{"lower", "timeout", gnerr.Timeout},
+		{"ds", "DATA_SOURCE_UNKNOWN", gnerr.DataSourceUnknown},
+		{"no error", "no_error", gnerr.NoError},
+		{"unknown", "UNKNOWN", gnerr.Unknown},
+	}
+
+	for _, v := range tests {
+		res := gnerr.New(v.inp)
+		assert.Equal(v.out, res, v.msg)
+		assert.Equal(res, gnerr.New(res.ID()), v.msg)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		code   gnerr.Code
+		status int
+	}{
+		{gnerr.NoError, http.StatusOK},
+		{gnerr.Unknown, http.StatusInternalServerError},
+		{gnerr.TooManyNames, http.StatusRequestEntityTooLarge},
+		{gnerr.ParseFailure, http.StatusUnprocessableEntity},
+		{gnerr.DataSourceUnknown, http.StatusBadRequest},
+		{gnerr.Timeout, http.StatusGatewayTimeout},
+		{gnerr.IncompatibleVersion, http.StatusConflict},
+		{gnerr.Code(100), http.StatusInternalServerError},
+	}
+
+	for _, v := range tests {
+		assert.Equal(v.status, v.code.HTTPStatus(), v.code.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	assert := assert.New(t)
+	enc := gnfmt.GNjson{}
+	codes := []gnerr.Code{gnerr.Timeout, gnerr.ParseFailure}
+	res, err := enc.Encode(codes)
+	assert.Nil(err)
+	assert.Equal(`["TIMEOUT","PARSE_FAILURE"]`, string(res))
+	var codes2 []gnerr.Code
+	err = enc.Decode(res, &codes2)
+	assert.Nil(err)
+	assert.Equal(codes, codes2)
+
+	err = enc.Decode([]byte(`"timeout"`), &codes2[0])
+	assert.Nil(err)
+	assert.Equal(gnerr.Timeout, codes2[0])
+
+	err = enc.Decode([]byte(`"NOPE"`), &codes2[0])
+	assert.Contains(err.Error(), "cannot decode as an error Code")
+}
+
+func TestNameErrorCode(t *testing.T) {
+	assert := assert.New(t)
+	enc := gnfmt.GNjson{}
+	res, err := enc.Encode(verifier.Name{ID: "1", Name: "Bubo"})
+	assert.Nil(err)
+	assert.NotContains(string(res), "errorCode")
+
+	res, err = enc.Encode(verifier.Name{ErrorCode: gnerr.Unknown})
+	assert.Nil(err)
+	assert.Contains(string(res), `"errorCode":"UNKNOWN"`)
+
+	var name1 verifier.Name
+	err = enc.Decode([]byte(`{"id":"1"}`), &name1)
+	assert.Nil(err)
+	assert.Equal(gnerr.NoError, name1.ErrorCode)
+
+	name := verifier.Name{ErrorCode: gnerr.Timeout}
+	res, err = enc.Encode(name)
+	assert.Nil(err)
+	assert.Contains(string(res), `"errorCode":"TIMEOUT"`)
+	var name2 verifier.Name
+	err = enc.Decode(res, &name2)
+	assert.Nil(err)
+	assert.Equal(gnerr.Timeout, name2.ErrorCode)
+}
+
+func TestError(t *testing.T) {
+	assert := assert.New(t)
+	base := errors.New("parser crashed")
+	err := gnerr.NewError(gnerr.ParseFailure, base, "Cannot parse <em>%s</em>", []any{"Bubo"})
+	assert.Equal("PARSE_FAILURE: parser crashed", err.Error())
+	assert.Equal("Cannot parse Bubo", err.Render(gnlib.PlainRenderer{}))
+
+	wrapped := fmt.Errorf("batch failed: %w", err)
+	assert.True(errors.Is(wrapped, base))
+	assert.Equal(gnerr.ParseFailure, gnerr.CodeOf(wrapped))
+	assert.Equal(gnerr.Unknown, gnerr.CodeOf(base))
+	assert.Equal(gnerr.NoError, gnerr.CodeOf(nil))
+
+	var gnErr gnlib.Error
+	assert.True(errors.As(wrapped, &gnErr))
+	assert.Equal("Cannot parse \033[33mBubo\033[0m", gnErr.UserMessage())
+
+	res, jerr := gnfmt.GNjson{}.Encode(err)
+	assert.Nil(jerr)
+	assert.Equal(`{"code":"PARSE_FAILURE","status":422,"message":"Cannot parse Bubo"}`, string(res))
+
+	err = gnerr.NewError(gnerr.Timeout, nil, "", nil)
+	assert.Equal("TIMEOUT", err.Error())
+	assert.Equal("The request timed out", err.Render(gnlib.PlainRenderer{}))
+}
@@ -0,0 +1,127 @@
+package gnvers
+
+import (
+	"fmt"
+
+	"github.com/gnames/gnlib"
+	"github.com/gnames/gnlib/ent/gnerr"
+)
+
+// IncompatibleError is returned when a client and a server cannot work
+// together. It implements gnlib.Error interface, so it provides
+// a user-friendly message with advice what to upgrade.
+type IncompatibleError struct {
+	// Client is the version of the client.
+	Client Version
+
+	// Server is the version of the server.
+	Server Version
+
+	// ClientOutdated is true if the client has to be upgraded, and false
+	// if the server is too old for the client.
+	ClientOutdated bool
+
+	// Required is the client version or the API version that is needed
+	// to make the client and the server compatible.
+	Required string
+
+	gnlib.MessageBase
+}
+
+// Error implements error interface.
+func (e *IncompatibleError) Error() string {
+	return "incompatible versions: " + e.Render(gnlib.PlainRenderer{})
+}
+
+// GNError converts the error to gnerr.Error with IncompatibleVersion
+// code, for example to send it in an HTTP response.
+func (e *IncompatibleError) GNError() *gnerr.Error {
+	return gnerr.NewError(gnerr.IncompatibleVersion, e, e.Msg, e.Vars)
+}
+
+// Compatible checks if a client can work with a server. It returns
+// *IncompatibleError if the client version is older than the
+// MinClientVersion of the server, if the client uses an older major API
+// version than the server provides, or if the client needs a newer API
+// version than the server provides. Checks that lack a version on either
+// side are skipped. If a version cannot be parsed, the parsing error is
+// returned.
+//
+// Example:
+//
+//	if err := gnvers.Compatible(client, server); err != nil {
+//		var incErr *gnvers.IncompatibleError
+//		if errors.As(err, &incErr) {
+//			fmt.Println(incErr.UserMessage())
+//		}
+//	}
+func Compatible(client, server Version) error {
+	if client.Version != "" && server.MinClientVersion != "" {
+		cv, err := parsePartial(client.Version)
+		if err != nil {
+			return fmt.Errorf("client version: %w", err)
+		}
+		minV, err := parsePartial(server.MinClientVersion)
+		if err != nil {
+			return fmt.Errorf("minimal client version: %w", err)
+		}
+		if cv.Less(minV) {
+			return newIncompatible(client, server, true, server.MinClientVersion,
+				"Client version <em>%s</em> is too old for the server "+
+					"<em>%s</em>, please upgrade the client to <em>%s</em> "+
+					"or newer",
+				client.Version, server.Version, server.MinClientVersion,
+			)
+		}
+	}
+
+	if client.APIVersion == "" || server.APIVersion == "" {
+		return nil
+	}
+	ca, err := parsePartial(client.APIVersion)
+	if err != nil {
+		return fmt.Errorf("client API version: %w", err)
+	}
+	sa, err := parsePartial(server.APIVersion)
+	if err != nil {
+		return fmt.Errorf("server API version: %w", err)
+	}
+	switch {
+	case ca.Major < sa.Major:
+		return newIncompatible(client, server, true, fmt.Sprintf("v%d", sa.Major),
+			"Client uses API <em>%s</em>, but the server provides API "+
+				"<em>%s</em>, please upgrade the client",
+			client.APIVersion, server.APIVersion,
+		)
+	case ca.Major > sa.Major || ca.Minor > sa.Minor:
+		return newIncompatible(client, server, false, client.APIVersion,
+			"Client needs API <em>%s</em>, but the server provides API "+
+				"<em>%s</em>, please use a newer server or an older client",
+			client.APIVersion, server.APIVersion,
+		)
+	}
+	return nil
+}
+
+func newIncompatible(
+	client, server Version,
+	clientOutdated bool,
+	required string,
+	msg string,
+	vars ...any,
+) *IncompatibleError {
+	return &IncompatibleError{
+		Client:         client,
+		Server:         server,
+		ClientOutdated: clientOutdated,
+		Required:       required,
+		MessageBase:    gnlib.NewMessage("<warning>"+msg+"</warning>", vars),
+	}
+}
+
+// parsePartial parses a version that can omit minor and patch numbers,
+// like "v1" for API versions.
+func parsePartial(s string) (gnlib.Version, error) {
+	res, _, err := gnlib.ParsePartialVersion(s)
+	return res, err
+}
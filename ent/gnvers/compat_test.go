@@ -0,0 +1,98 @@
+package gnvers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/gnames/gnlib/ent/gnerr"
+	"github.com/gnames/gnlib/ent/gnvers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatible(t *testing.T) {
+	assert := assert.New(t)
+	server := gnvers.Version{
+		Version:          "v2.3.0",
+		APIVersion:       "v2.1",
+		MinClientVersion: "v1.1.0",
+	}
+	tests := []struct {
+		msg            string
+		client         gnvers.Version
+		compatible     bool
+		clientOutdated bool
+		required       string
+		userMsg        string
+	}{
+		{"same", gnvers.Version{Version: "v1.1.0", APIVersion: "v2.1"}, true, false, "", ""},
+		{"no info", gnvers.Version{}, true, false, "", ""},
+		{"older api minor", gnvers.Version{Version: "v1.5.0", APIVersion: "v2"}, true, false, "", ""},
+		{
+			"old client", gnvers.Version{Version: "v1.0.9", APIVersion: "v2"}, false, true, "v1.1.0",
+			"Client version v1.0.9 is too old for the server v2.3.0, " +
+				"please upgrade the client to v1.1.0 or newer",
+		},
+		{
+			"pre-release client", gnvers.Version{Version: "v1.1.0-rc.1"}, false, true, "v1.1.0",
+			"Client version v1.1.0-rc.1 is too old for the server v2.3.0, " +
+				"please upgrade the client to v1.1.0 or newer",
+		},
+		{
+			"old api", gnvers.Version{Version: "v1.2.0", APIVersion: "v1.9"}, false, true, "v2",
+			"Client uses API v1.9, but the server provides API v2.1, please upgrade the client",
+		},
+		{
+			"new api", gnvers.Version{Version: "v3.0.0", APIVersion: "v3"}, false, false, "v3",
+			"Client needs API v3, but the server provides API v2.1, " +
+				"please use a newer server or an older client",
+		},
+		{
+			"new api minor", gnvers.Version{APIVersion: "v2.2"}, false, false, "v2.2",
+			"Client needs API v2.2, but the server provides API v2.1, " +
+				"please use a newer server or an older client",
+		},
+	}
+
+	for _, v := range tests {
+		err := gnvers.Compatible(v.client, server)
+		if v.compatible {
+			assert.Nil(err, v.msg)
+			continue
+		}
+		var incErr *gnvers.IncompatibleError
+		assert.ErrorAs(err, &incErr, v.msg)
+		assert.Equal(v.clientOutdated, incErr.ClientOutdated, v.msg)
+		assert.Equal(v.required, incErr.Required, v.msg)
+		assert.Equal(v.client, incErr.Client, v.msg)
+		assert.Equal(server, incErr.Server, v.msg)
+		assert.Equal(v.userMsg, incErr.Render(gnlib.PlainRenderer{}), v.msg)
+		assert.Equal("incompatible versions: "+v.userMsg, err.Error(), v.msg)
+
+		var gnErr gnlib.Error
+		assert.True(errors.As(err, &gnErr), v.msg)
+		assert.Equal(gnerr.IncompatibleVersion, gnerr.CodeOf(incErr.GNError()), v.msg)
+	}
+}
+
+func TestCompatibleErr(t *testing.T) {
+	assert := assert.New(t)
+	server := gnvers.Version{Version: "v2.3.0", APIVersion: "v2", MinClientVersion: "v1.1.0"}
+	tests := []struct {
+		msg            string
+		client, server gnvers.Version
+	}{
+		{"client version", gnvers.Version{Version: "latest"}, server},
+		{"client api", gnvers.Version{APIVersion: "two"}, server},
+		{"server api", gnvers.Version{APIVersion: "v2"}, gnvers.Version{APIVersion: "v2.x"}},
+		{"min client", gnvers.Version{Version: "v1.0.0"}, gnvers.Version{MinClientVersion: "1.x"}},
+	}
+
+	for _, v := range tests {
+		err := gnvers.Compatible(v.client, v.server)
+		var vErr *gnlib.VersionError
+		assert.ErrorAs(err, &vErr, v.msg)
+		var incErr *gnvers.IncompatibleError
+		assert.False(errors.As(err, &incErr), v.msg)
+	}
+}
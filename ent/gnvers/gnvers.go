@@ -1,5 +1,9 @@
+// Package gnvers provides information about versions of Global Names
+// applications.
 package gnvers
 
+import "runtime/debug"
+
 // @Description Version provides information about the version
 // @Description of an application.
 type Version struct {
@@ -9,4 +13,72 @@ type Version struct {
 	// Build contains the timestamp or other details
 	// indicating when the app was compiled.
 	Build string `json:"build" example:"2023-08-03_18:58:38UTC"`
+
+	// Revision is the VCS revision (commit hash) the app was built from.
+	Revision string `json:"revision,omitempty" example:"4f3c2b1a9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a"`
+
+	// CommitTime is the time of the Revision commit in RFC 3339 format.
+	CommitTime string `json:"commitTime,omitempty" example:"2023-08-03T18:50:12Z"`
+
+	// Dirty is true if the app was built from a working tree with
+	// uncommitted changes.
+	Dirty bool `json:"dirty,omitempty" example:"false"`
+
+	// GoVersion is the version of Go used to build the app.
+	GoVersion string `json:"goVersion,omitempty" example:"go1.25.1"`
+
+	// APIVersion is the version of the API the app provides (servers)
+	// or uses (clients), for example "v1" or "v1.2". Apps with different
+	// major API versions cannot work together.
+	APIVersion string `json:"apiVersion,omitempty" example:"v1.2"`
+
+	// MinClientVersion is the oldest client version a server supports.
+	MinClientVersion string `json:"minClientVersion,omitempty" example:"v1.1.0"`
+}
+
+// New creates a Version from the build information embedded by Go.
+// The version and build arguments usually come from variables set with
+// -ldflags, and take precedence if they are not empty. If the version is
+// empty, the module version is used, if the build is empty, the commit
+// time is used.
+//
+// Example:
+//
+//	// set by: go build -ldflags "-X main.version=v1.2.3"
+//	var version, build string
+//
+//	ver := gnvers.New(version, build)
+func New(version, build string) Version {
+	bi, _ := debug.ReadBuildInfo()
+	return FromBuildInfo(bi, version, build)
+}
+
+// FromBuildInfo creates a Version from the given build information, the
+// same way as New does. The build information can be nil.
+func FromBuildInfo(bi *debug.BuildInfo, version, build string) Version {
+	res := Version{Version: version, Build: build}
+	if bi == nil {
+		return res
+	}
+
+	res.GoVersion = bi.GoVersion
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			res.Revision = s.Value
+		case "vcs.time":
+			res.CommitTime = s.Value
+		case "vcs.modified":
+			res.Dirty = s.Value == "true"
+		}
+	}
+
+	// "(devel)" is the version of a module built from its own directory
+	if res.Version == "" && bi.Main.Version != "(devel)" {
+		res.Version = bi.Main.Version
+	}
+	if res.Build == "" {
+		res.Build = res.CommitTime
+	}
+	return res
 }
@@ -0,0 +1,82 @@
+package gnvers_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/gnames/gnlib/ent/gnvers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	assert := assert.New(t)
+	bi := &debug.BuildInfo{
+		GoVersion: "go1.25.1",
+		Main:      debug.Module{Path: "github.com/gnames/gnverifier", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "4f3c2b1"},
+			{Key: "vcs.time", Value: "2023-08-03T18:50:12Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	devel := *bi
+	devel.Main.Version = "(devel)"
+
+	tests := []struct {
+		msg            string
+		bi             *debug.BuildInfo
+		version, build string
+		resVer, resBld string
+	}{
+		{"build info", bi, "", "", "v1.2.3", "2023-08-03T18:50:12Z"},
+		{"ldflags", bi, "v1.3.0", "2023-08-04_10:00:00UTC", "v1.3.0", "2023-08-04_10:00:00UTC"},
+		{"ldflags version", bi, "v1.3.0", "", "v1.3.0", "2023-08-03T18:50:12Z"},
+		{"devel", &devel, "", "", "", "2023-08-03T18:50:12Z"},
+	}
+
+	for _, v := range tests {
+		res := gnvers.FromBuildInfo(v.bi, v.version, v.build)
+		assert.Equal(v.resVer, res.Version, v.msg)
+		assert.Equal(v.resBld, res.Build, v.msg)
+		assert.Equal("4f3c2b1", res.Revision, v.msg)
+		assert.Equal("2023-08-03T18:50:12Z", res.CommitTime, v.msg)
+		assert.True(res.Dirty, v.msg)
+		assert.Equal("go1.25.1", res.GoVersion, v.msg)
+	}
+
+	res := gnvers.FromBuildInfo(nil, "v1.0.0", "")
+	assert.Equal(gnvers.Version{Version: "v1.0.0"}, res)
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	res := gnvers.New("v1.0.0", "today")
+	assert.Equal("v1.0.0", res.Version)
+	assert.Equal("today", res.Build)
+	assert.Equal(runtime.Version(), res.GoVersion)
+}
+
+func TestVersionJSON(t *testing.T) {
+	assert := assert.New(t)
+	bs, err := json.Marshal(gnvers.Version{Version: "v1.0.0", Build: "today"})
+	assert.Nil(err)
+	assert.Equal(`{"version":"v1.0.0","build":"today"}`, string(bs))
+
+	v := gnvers.Version{
+		Version: "v1.0.0", Build: "today", Revision: "abc",
+		CommitTime: "2023-08-03T18:50:12Z", Dirty: true, GoVersion: "go1.25.1",
+	}
+	bs, err = json.Marshal(v)
+	assert.Nil(err)
+	assert.Equal(
+		`{"version":"v1.0.0","build":"today","revision":"abc",`+
+			`"commitTime":"2023-08-03T18:50:12Z","dirty":true,"goVersion":"go1.25.1"}`,
+		string(bs),
+	)
+	var v2 gnvers.Version
+	assert.Nil(json.Unmarshal(bs, &v2))
+	assert.Equal(v, v2)
+}
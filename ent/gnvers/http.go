@@ -0,0 +1,116 @@
+package gnvers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gnames/gnlib/ent/gnerr"
+)
+
+// HTTP headers that carry version information between clients and
+// servers.
+const (
+	HeaderVersion          = "X-GN-Version"
+	HeaderAPIVersion       = "X-GN-API-Version"
+	HeaderMinClientVersion = "X-GN-Min-Client-Version"
+)
+
+// SetHeader adds non-empty version fields to HTTP headers.
+func (v Version) SetHeader(h http.Header) {
+	for k, val := range map[string]string{
+		HeaderVersion:          v.Version,
+		HeaderAPIVersion:       v.APIVersion,
+		HeaderMinClientVersion: v.MinClientVersion,
+	} {
+		if val != "" {
+			h.Set(k, val)
+		}
+	}
+}
+
+// FromHeader creates a Version from HTTP headers.
+func FromHeader(h http.Header) Version {
+	return Version{
+		Version:          h.Get(HeaderVersion),
+		APIVersion:       h.Get(HeaderAPIVersion),
+		MinClientVersion: h.Get(HeaderMinClientVersion),
+	}
+}
+
+// Middleware returns an HTTP middleware that adds the server version to
+// every response and rejects requests from incompatible clients. The
+// client version is taken from request headers, clients that do not
+// send them are not checked. An incompatible client receives
+// gnerr.Error with IncompatibleVersion code as JSON, a client with
+// invalid version headers receives gnerr.Error with InvalidInput code.
+//
+// Example:
+//
+//	ver := gnvers.New(version, build)
+//	ver.APIVersion, ver.MinClientVersion = "v1", "v1.1.0"
+//	http.ListenAndServe(":8080", gnvers.Middleware(ver)(mux))
+func Middleware(server Version) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server.SetHeader(w.Header())
+			err := Compatible(FromHeader(r.Header), server)
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var gnErr *gnerr.Error
+			var incErr *IncompatibleError
+			if errors.As(err, &incErr) {
+				gnErr = incErr.GNError()
+			} else {
+				gnErr = gnerr.NewError(gnerr.InvalidInput, err,
+					"<warning>Invalid version headers: %s</warning>",
+					[]any{err.Error()},
+				)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(gnErr.Code.HTTPStatus())
+			_ = json.NewEncoder(w).Encode(gnErr)
+		})
+	}
+}
+
+// NewTransport returns an http.RoundTripper that sends the client
+// version with every request and checks the version of the server in
+// responses. If the server is incompatible, the response is discarded
+// and *IncompatibleError is returned. If base is nil,
+// http.DefaultTransport is used.
+//
+// Example:
+//
+//	ver := gnvers.New(version, build)
+//	ver.APIVersion = "v1"
+//	client := &http.Client{Transport: gnvers.NewTransport(ver, nil)}
+func NewTransport(client Version, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{client: client, base: base}
+}
+
+type transport struct {
+	client Version
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	t.client.SetHeader(r.Header)
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = Compatible(t.client, FromHeader(resp.Header)); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
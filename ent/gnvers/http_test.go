@@ -0,0 +1,123 @@
+package gnvers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnames/gnlib/ent/gnvers"
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(server gnvers.Version) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+	return httptest.NewServer(gnvers.Middleware(server)(mux))
+}
+
+func TestHeader(t *testing.T) {
+	assert := assert.New(t)
+	v := gnvers.Version{Version: "v1.0.0", Build: "today", APIVersion: "v1"}
+	h := make(http.Header)
+	v.SetHeader(h)
+	assert.Equal("v1.0.0", h.Get(gnvers.HeaderVersion))
+	assert.Equal("v1", h.Get(gnvers.HeaderAPIVersion))
+	assert.Empty(h.Values(gnvers.HeaderMinClientVersion))
+	assert.Equal(gnvers.Version{Version: "v1.0.0", APIVersion: "v1"}, gnvers.FromHeader(h))
+}
+
+func TestMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	server := gnvers.Version{Version: "v2.3.0", APIVersion: "v2", MinClientVersion: "v1.1.0"}
+	ts := testServer(server)
+	defer ts.Close()
+
+	tests := []struct {
+		msg    string
+		client gnvers.Version
+		status int
+		code   string
+	}{
+		{"no headers", gnvers.Version{}, http.StatusOK, ""},
+		{"compatible", gnvers.Version{Version: "v1.2.0", APIVersion: "v2"}, http.StatusOK, ""},
+		{
+			"old client", gnvers.Version{Version: "v1.0.0"},
+			http.StatusConflict, "INCOMPATIBLE_VERSION",
+		},
+		{
+			"old server", gnvers.Version{APIVersion: "v3"},
+			http.StatusConflict, "INCOMPATIBLE_VERSION",
+		},
+		{
+			"bad header", gnvers.Version{Version: "latest"},
+			http.StatusBadRequest, "INVALID_INPUT",
+		},
+	}
+
+	for _, v := range tests {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/ping", nil)
+		assert.Nil(err)
+		v.client.SetHeader(req.Header)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.status, resp.StatusCode, v.msg)
+		assert.Equal(server, gnvers.FromHeader(resp.Header), v.msg)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Nil(err, v.msg)
+		if v.code == "" {
+			assert.Equal("pong", string(body), v.msg)
+			continue
+		}
+		var res struct {
+			Code    string `json:"code"`
+			Status  int    `json:"status"`
+			Message string `json:"message"`
+		}
+		assert.Nil(json.Unmarshal(body, &res), v.msg)
+		assert.Equal(v.code, res.Code, v.msg)
+		assert.Equal(v.status, res.Status, v.msg)
+		assert.NotEmpty(res.Message, v.msg)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	assert := assert.New(t)
+	ts := testServer(gnvers.Version{Version: "v2.3.0", APIVersion: "v2.1"})
+	defer ts.Close()
+
+	tests := []struct {
+		msg            string
+		client         gnvers.Version
+		ok             bool
+		clientOutdated bool
+	}{
+		{"compatible", gnvers.Version{Version: "v1.0.0", APIVersion: "v2"}, true, false},
+		{"old server", gnvers.Version{Version: "v3.0.0", APIVersion: "v2.2"}, false, false},
+		{"old client", gnvers.Version{Version: "v1.0.0", APIVersion: "v1"}, false, true},
+	}
+
+	for _, v := range tests {
+		client := &http.Client{Transport: gnvers.NewTransport(v.client, nil)}
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/ping", nil)
+		assert.Nil(err)
+		resp, err := client.Do(req)
+		// the original request is not modified
+		assert.Empty(req.Header.Get(gnvers.HeaderVersion), v.msg)
+		if v.ok {
+			assert.Nil(err, v.msg)
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Equal("pong", string(body), v.msg)
+			continue
+		}
+		var incErr *gnvers.IncompatibleError
+		assert.True(errors.As(err, &incErr), v.msg)
+		assert.Equal(v.clientOutdated, incErr.ClientOutdated, v.msg)
+	}
+}
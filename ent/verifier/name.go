@@ -3,6 +3,7 @@ package verifier
 import (
 	"strings"
 
+	"github.com/gnames/gnlib/ent/gnerr"
 	"github.com/gnames/gnstats/ent/stats"
 )
 
@@ -61,6 +62,11 @@ type Name struct {
 	// Error provides an error message, if any. If error is not empty, the match
 	// failed because of a bug in the service.
 	Error string `json:"error,omitempty"`
+
+	// ErrorCode provides the type of the error, if any. Clients can use it
+	// to distinguish failures without parsing the Error message. It is
+	// gnerr.NoError and is omitted from JSON if there was no error.
+	ErrorCode gnerr.Code `json:"errorCode,omitempty"`
 }
 
 // DataSourceDetails describe data-source and found best match.
@@ -0,0 +1,108 @@
+package gnlib
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// IndexError is an error that happened while processing an element of
+// a slice. It keeps the index of the element.
+type IndexError struct {
+	// Index is the index of the element that caused the error.
+	Index int
+
+	// Err is the original error.
+	Err error
+}
+
+// Error implements error interface.
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the original error.
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is an error created from a recovered panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error implements error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// SafeCall calls f and converts a panic into PanicError.
+//
+// Example:
+//
+//	err := SafeCall(func() error { return process(item) })
+//	var pErr *PanicError
+//	if errors.As(err, &pErr) { log(pErr.Stack) }
+func SafeCall(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// BatchError aggregates errors that happened while processing elements
+// of a slice. Errors are sorted by index.
+type BatchError struct {
+	// Errors contains errors of the failed elements.
+	Errors []*IndexError
+}
+
+// Error implements error interface.
+func (e *BatchError) Error() string {
+	res := make([]string, len(e.Errors))
+	for i := range e.Errors {
+		res[i] = e.Errors[i].Error()
+	}
+	return strings.Join(res, "\n")
+}
+
+// Unwrap returns errors of the failed elements. It allows errors.Is and
+// errors.As to find the original errors.
+func (e *BatchError) Unwrap() []error {
+	res := make([]error, len(e.Errors))
+	for i := range e.Errors {
+		res[i] = e.Errors[i]
+	}
+	return res
+}
+
+// Indexes returns indices of the failed elements.
+func (e *BatchError) Indexes() []int {
+	res := make([]int, len(e.Errors))
+	for i := range e.Errors {
+		res[i] = e.Errors[i].Index
+	}
+	return res
+}
+
+// newBatchError returns BatchError if there are errors, or nil otherwise.
+func newBatchError(errs []*IndexError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs}
+}
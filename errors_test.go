@@ -0,0 +1,22 @@
+package gnlib_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeCall(t *testing.T) {
+	assert := assert.New(t)
+	errTest := errors.New("test")
+	assert.Nil(gnlib.SafeCall(func() error { return nil }))
+	assert.Equal(errTest, gnlib.SafeCall(func() error { return errTest }))
+
+	err := gnlib.SafeCall(func() error { panic(errTest) })
+	var pErr *gnlib.PanicError
+	assert.ErrorAs(err, &pErr)
+	assert.ErrorIs(err, errTest)
+	assert.Contains(string(pErr.Stack), "errors_test.go")
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var versionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+(?:\.\d+)?$`)
@@ -31,6 +32,188 @@ func FilterFunc[T any](s []T, f func(T) bool) []T {
 	return result
 }
 
+// MapErr applies a function that can fail to each element of a slice
+// and returns a new slice in the same order. It stops at the first error
+// and returns it wrapped into IndexError.
+func MapErr[T any, U any](s []T, f func(T) (U, error)) ([]U, error) {
+	result := make([]U, len(s))
+	for i, v := range s {
+		res, err := f(v)
+		if err != nil {
+			return nil, &IndexError{Index: i, Err: err}
+		}
+		result[i] = res
+	}
+	return result, nil
+}
+
+// MapErrAll is similar to MapErr, but it processes all elements. Failed
+// elements get zero values in the result, their errors are aggregated
+// into BatchError.
+func MapErrAll[T any, U any](s []T, f func(T) (U, error)) ([]U, error) {
+	result := make([]U, len(s))
+	var errs []*IndexError
+	for i, v := range s {
+		res, err := f(v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		result[i] = res
+	}
+	return result, newBatchError(errs)
+}
+
+// FilterErr returns a new slice containing only the elements of s for
+// which filter function returns true. It stops at the first error and
+// returns it wrapped into IndexError.
+func FilterErr[T any](s []T, f func(T) (bool, error)) ([]T, error) {
+	result := make([]T, 0, len(s))
+	for i, v := range s {
+		ok, err := f(v)
+		if err != nil {
+			return nil, &IndexError{Index: i, Err: err}
+		}
+		if ok {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// FilterErrAll is similar to FilterErr, but it processes all elements.
+// Failed elements are excluded from the result, their errors are
+// aggregated into BatchError.
+func FilterErrAll[T any](s []T, f func(T) (bool, error)) ([]T, error) {
+	result := make([]T, 0, len(s))
+	var errs []*IndexError
+	for i, v := range s {
+		ok, err := f(v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		if ok {
+			result = append(result, v)
+		}
+	}
+	return result, newBatchError(errs)
+}
+
+// Reduce combines elements of a slice into one value, starting from init.
+func Reduce[T any, A any](s []T, init A, f func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// ReduceErr combines elements of a slice into one value, starting from
+// init. It stops at the first error and returns the accumulated value so
+// far together with the error wrapped into IndexError.
+func ReduceErr[T any, A any](s []T, init A, f func(A, T) (A, error)) (A, error) {
+	acc := init
+	for i, v := range s {
+		res, err := f(acc, v)
+		if err != nil {
+			return acc, &IndexError{Index: i, Err: err}
+		}
+		acc = res
+	}
+	return acc, nil
+}
+
+// ReduceErrAll is similar to ReduceErr, but it skips failed elements
+// and continues with the accumulated value. Errors are aggregated into
+// BatchError.
+func ReduceErrAll[T any, A any](s []T, init A, f func(A, T) (A, error)) (A, error) {
+	acc := init
+	var errs []*IndexError
+	for i, v := range s {
+		res, err := f(acc, v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		acc = res
+	}
+	return acc, newBatchError(errs)
+}
+
+// GroupBy groups elements of a slice by a key. Elements in each group
+// keep their original order.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	res := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		res[k] = append(res[k], v)
+	}
+	return res
+}
+
+// KeyBy creates a lookup map from a slice using a key function. If
+// several elements have the same key, the first one is kept.
+func KeyBy[T any, K comparable](s []T, key func(T) K) map[K]T {
+	res := make(map[K]T, len(s))
+	for _, v := range s {
+		k := key(v)
+		if _, ok := res[k]; !ok {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Partition splits a slice into elements for which the function returns
+// true, and the rest. Both slices keep the original order.
+func Partition[T any](s []T, f func(T) bool) ([]T, []T) {
+	var yes, no []T
+	for _, v := range s {
+		if f(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// Uniq returns a new slice without duplicates. The first occurrence of
+// each element is kept in the original order.
+func Uniq[T comparable](s []T) []T {
+	return UniqBy(s, func(v T) T { return v })
+}
+
+// UniqBy returns a new slice without elements that have duplicate keys.
+// The first element with each key is kept in the original order.
+func UniqBy[T any, K comparable](s []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		k := key(v)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Flatten concatenates slices into one slice.
+func Flatten[T any](s [][]T) []T {
+	var l int
+	for i := range s {
+		l += len(s[i])
+	}
+	result := make([]T, 0, l)
+	for i := range s {
+		result = append(result, s[i]...)
+	}
+	return result
+}
+
 // SliceMap takes a slice and returns back a lookup map which allows to find
 // index for each element of the slice. If the value happens several times
 // in the slice, the index corresponds to the first matching element.
@@ -47,7 +230,8 @@ func SliceMap[T comparable](s []T) map[T]int {
 // ChunkChannel reads from an input channel and sends slices (chunks) of
 // up to `chunkSize` items to an output channel. If the input channel is
 // closed, any remaining items are sent as a final slice. The output channel
-// is closed after all data is processed.
+// is closed after all data is processed, or when the context is cancelled.
+// Use Chunker to get items that were not delivered after a cancellation.
 //
 // Parameters:
 //   - `ctx`: Context for cancellation.
@@ -65,29 +249,37 @@ func SliceMap[T comparable](s []T) map[T]int {
 //	for chunk := range chunked { fmt.Println(chunk) }
 //	// Output: [1 2 3] [4 5 6] [7 8 9] [10]
 func ChunkChannel[T any](ctx context.Context, input <-chan T, chunkSize int) <-chan []T {
-	output := make(chan []T)
-	go func() {
-		defer close(output) // Close output channel when done
-		var chunk []T       // Buffer to collect items
-		for {
-			select {
-			case <-ctx.Done(): // Handle cancellation
-				return
-			case val, ok := <-input:
-				if !ok { // Input channel closed
-					if len(chunk) > 0 {
-						output <- chunk // Send remaining items
-					}
-					return
-				}
-				chunk = append(chunk, val)
-				if len(chunk) == chunkSize {
-					output <- chunk // Send full chunk
-					chunk = nil     // Reset chunk
-				}
-			}
-		}
-	}()
+	output, _ := Chunker[T]{Size: chunkSize}.Run(ctx, input)
+	return output
+}
+
+// ChunkChannelTimeout is similar to ChunkChannel, but it does not let
+// a partial chunk wait longer than `maxDelay` since its first item was
+// received. It is useful when items come slowly, but consumers need
+// bounded response times. If `maxDelay` is not positive, chunks are
+// sent only when they are full or when the input channel is closed.
+//
+// Parameters:
+//   - `ctx`: Context for cancellation.
+//   - `input`: Input channel.
+//   - `chunkSize`: Max items per chunk.
+//   - `maxDelay`: Max time between the first item of a chunk and
+//     sending the chunk.
+//
+// Returns:
+//   - Output channel with slices of items.
+//
+// Example:
+//
+//	chunked := ChunkChannelTimeout(ctx, names, 1000, 200*time.Millisecond)
+//	for chunk := range chunked { verify(chunk) }
+func ChunkChannelTimeout[T any](
+	ctx context.Context,
+	input <-chan T,
+	chunkSize int,
+	maxDelay time.Duration,
+) <-chan []T {
+	output, _ := Chunker[T]{Size: chunkSize, MaxDelay: maxDelay}.Run(ctx, input)
 	return output
 }
 
@@ -100,7 +292,9 @@ func IsVersion(s string) bool {
 // It returns 0 if the versions are equal, 1 if a is greater than b, and -1
 // if a is less than b. The version strings are expected to be in a format
 // that can be split into integer components for comparison,
-// such as "1.2.3" or "1.0.0".
+// such as "1.2.3" or "1.0.0". Non-numeric components are treated as 0,
+// use ParseVersion and Version.Compare for Semantic Versioning 2.0
+// precedence with pre-releases.
 func CmpVersion(a, b string) int {
 	if a == b {
 		return 0
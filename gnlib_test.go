@@ -2,10 +2,14 @@ package gnlib_test
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gnames/gnlib"
+	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,6 +31,154 @@ func TestFilter(t *testing.T) {
 	assert.Equal([]string{"a", "c"}, res)
 }
 
+func TestMapErr(t *testing.T) {
+	assert := assert.New(t)
+	res, err := gnlib.MapErr([]string{"1", "2", "3"}, strconv.Atoi)
+	assert.Nil(err)
+	assert.Equal([]int{1, 2, 3}, res)
+
+	res, err = gnlib.MapErr([]string{"1", "x", "3", "y"}, strconv.Atoi)
+	assert.Nil(res)
+	assert.ErrorIs(err, strconv.ErrSyntax)
+	var idxErr *gnlib.IndexError
+	assert.ErrorAs(err, &idxErr)
+	assert.Equal(1, idxErr.Index)
+}
+
+func TestMapErrAll(t *testing.T) {
+	assert := assert.New(t)
+	res, err := gnlib.MapErrAll([]string{"1", "2"}, strconv.Atoi)
+	assert.Nil(err)
+	assert.Equal([]int{1, 2}, res)
+
+	res, err = gnlib.MapErrAll([]string{"1", "x", "3", "y"}, strconv.Atoi)
+	assert.Equal([]int{1, 0, 3, 0}, res)
+	assert.ErrorIs(err, strconv.ErrSyntax)
+	var batchErr *gnlib.BatchError
+	assert.ErrorAs(err, &batchErr)
+	assert.Equal([]int{1, 3}, batchErr.Indexes())
+	assert.Equal(
+		"index 1: strconv.Atoi: parsing \"x\": invalid syntax\n"+
+			"index 3: strconv.Atoi: parsing \"y\": invalid syntax",
+		err.Error(),
+	)
+}
+
+func TestFilterErr(t *testing.T) {
+	assert := assert.New(t)
+	errEmpty := errors.New("empty")
+	f := func(s string) (bool, error) {
+		if s == "" {
+			return false, errEmpty
+		}
+		return s != "b", nil
+	}
+
+	res, err := gnlib.FilterErr([]string{"a", "b", "c"}, f)
+	assert.Nil(err)
+	assert.Equal([]string{"a", "c"}, res)
+
+	res, err = gnlib.FilterErr([]string{"a", "", "c"}, f)
+	assert.Nil(res)
+	assert.ErrorIs(err, errEmpty)
+
+	res, err = gnlib.FilterErrAll([]string{"a", "", "b", "c", ""}, f)
+	assert.Equal([]string{"a", "c"}, res)
+	var batchErr *gnlib.BatchError
+	assert.ErrorAs(err, &batchErr)
+	assert.Equal([]int{1, 4}, batchErr.Indexes())
+}
+
+func TestReduceErr(t *testing.T) {
+	assert := assert.New(t)
+	sum := func(acc int, s string) (int, error) {
+		i, err := strconv.Atoi(s)
+		return acc + i, err
+	}
+
+	res, err := gnlib.ReduceErr([]string{"1", "2", "3"}, 10, sum)
+	assert.Nil(err)
+	assert.Equal(16, res)
+
+	res, err = gnlib.ReduceErr([]string{"1", "x", "3"}, 10, sum)
+	assert.Equal(11, res)
+	var idxErr *gnlib.IndexError
+	assert.ErrorAs(err, &idxErr)
+	assert.Equal(1, idxErr.Index)
+
+	res, err = gnlib.ReduceErrAll([]string{"1", "x", "3"}, 10, sum)
+	assert.Equal(14, res)
+	var batchErr *gnlib.BatchError
+	assert.ErrorAs(err, &batchErr)
+	assert.Equal([]int{1}, batchErr.Indexes())
+}
+
+func TestReduce(t *testing.T) {
+	assert := assert.New(t)
+	res := gnlib.Reduce([]string{"a", "bb", "ccc"}, 0, func(acc int, s string) int {
+		return acc + len(s)
+	})
+	assert.Equal(6, res)
+	assert.Equal("x", gnlib.Reduce(nil, "x", func(acc string, s string) string {
+		return acc + s
+	}))
+}
+
+func TestGroupBy(t *testing.T) {
+	assert := assert.New(t)
+	type result struct {
+		dsID int
+		name string
+	}
+	data := []result{{1, "a"}, {3, "b"}, {1, "c"}, {2, "d"}}
+	res := gnlib.GroupBy(data, func(r result) int { return r.dsID })
+	assert.Equal(map[int][]result{
+		1: {{1, "a"}, {1, "c"}},
+		2: {{2, "d"}},
+		3: {{3, "b"}},
+	}, res)
+	assert.Empty(gnlib.GroupBy([]result{}, func(r result) int { return r.dsID }))
+}
+
+func TestKeyBy(t *testing.T) {
+	assert := assert.New(t)
+	res := gnlib.KeyBy([]string{"apple", "avocado", "banana"}, func(s string) byte {
+		return s[0]
+	})
+	assert.Equal(map[byte]string{'a': "apple", 'b': "banana"}, res)
+}
+
+func TestPartition(t *testing.T) {
+	assert := assert.New(t)
+	matched, noMatch := gnlib.Partition(
+		[]vlib.MatchTypeValue{vlib.Exact, vlib.NoMatch, vlib.Fuzzy, vlib.NoMatch},
+		func(mt vlib.MatchTypeValue) bool { return mt != vlib.NoMatch },
+	)
+	assert.Equal([]vlib.MatchTypeValue{vlib.Exact, vlib.Fuzzy}, matched)
+	assert.Equal([]vlib.MatchTypeValue{vlib.NoMatch, vlib.NoMatch}, noMatch)
+
+	yes, no := gnlib.Partition([]int{}, func(int) bool { return true })
+	assert.Nil(yes)
+	assert.Nil(no)
+}
+
+func TestUniq(t *testing.T) {
+	assert := assert.New(t)
+	res := gnlib.Uniq([]string{"Bubo", "Aus", "Bubo", "Cus", "Aus"})
+	assert.Equal([]string{"Bubo", "Aus", "Cus"}, res)
+	assert.Empty(gnlib.Uniq([]int{}))
+
+	res = gnlib.UniqBy([]string{"Bubo", "bubo", "Aus", "BUBO"}, strings.ToLower)
+	assert.Equal([]string{"Bubo", "Aus"}, res)
+}
+
+func TestFlatten(t *testing.T) {
+	assert := assert.New(t)
+	res := gnlib.Flatten([][]int{{1, 2}, {}, nil, {3}})
+	assert.Equal([]int{1, 2, 3}, res)
+	assert.Empty(gnlib.Flatten[int](nil))
+}
+
 func TestSliceMap(t *testing.T) {
 	assert := assert.New(t)
 	sm := gnlib.SliceMap([]int{1, 2, 3})
@@ -144,3 +296,76 @@ func TestChunkChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestChunkChannelTimeout(t *testing.T) {
+	assert := assert.New(t)
+	input := make(chan int)
+	var lastSent time.Time
+	go func() {
+		input <- 1
+		input <- 2
+		input <- 3
+		time.Sleep(100 * time.Millisecond)
+		lastSent = time.Now()
+		input <- 4
+		close(input)
+	}()
+
+	output := gnlib.ChunkChannelTimeout(context.Background(), input, 2, 10*time.Millisecond)
+	var result [][]int
+	var received []time.Time
+	for chunk := range output {
+		received = append(received, time.Now())
+		result = append(result, chunk)
+	}
+	assert.Equal([][]int{{1, 2}, {3}, {4}}, result)
+	// partial chunk was sent before the slow producer sent the next item
+	assert.True(received[1].Before(lastSent))
+}
+
+func TestChunkChannelTimeoutNoDelay(t *testing.T) {
+	assert := assert.New(t)
+	for _, delay := range []time.Duration{0, time.Hour} {
+		input := make(chan int)
+		go func() {
+			for i := 1; i <= 5; i++ {
+				input <- i
+			}
+			close(input)
+		}()
+		var result [][]int
+		for chunk := range gnlib.ChunkChannelTimeout(context.Background(), input, 2, delay) {
+			result = append(result, chunk)
+		}
+		assert.Equal([][]int{{1, 2}, {3, 4}, {5}}, result)
+	}
+}
+
+func TestChunkChannelTimeoutCancel(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan int, 10)
+	for i := range 10 {
+		input <- i
+	}
+	output := gnlib.ChunkChannelTimeout(ctx, input, 2, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case <-time.After(time.Second):
+		assert.Fail("output is not closed after cancellation")
+	case <-drain(output):
+	}
+}
+
+// drain reads a channel until it is closed and returns a channel that is
+// closed after that.
+func drain[T any](ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	return done
+}
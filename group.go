@@ -0,0 +1,137 @@
+package gnlib
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs tasks in goroutines with a limit on the number of tasks
+// that run at the same time. The context of the group is cancelled on
+// the first error, and panics in tasks are converted to PanicError.
+//
+// Example:
+//
+//	g := NewGroup(ctx, 8)
+//	for _, batch := range batches {
+//		g.Go(func(ctx context.Context) error {
+//			return verify(ctx, batch)
+//		})
+//	}
+//	if err := g.Wait(); err != nil {
+//		return err
+//	}
+type Group struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+	// byTask is true if the context was cancelled by an error of a task.
+	byTask bool
+	// skipped is true if Go did not start a task.
+	skipped bool
+}
+
+// NewGroup creates a Group that runs up to `limit` tasks at the same
+// time. If limit is less than 1, the number of tasks is not limited.
+func NewGroup(ctx context.Context, limit int) *Group {
+	res := &Group{parent: ctx}
+	res.ctx, res.cancel = context.WithCancelCause(ctx)
+	if limit > 0 {
+		res.sem = make(chan struct{}, limit)
+	}
+	return res
+}
+
+// Context returns the context of the group. It is cancelled on the
+// first error of a task, when the parent context is cancelled, or when
+// Wait returns.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs the task in a new goroutine. If the limit of running tasks is
+// reached, Go blocks until one of them finishes. If the context of the
+// group is cancelled, the task is not started, and Wait reports the
+// cause of the cancellation.
+func (g *Group) Go(f func(context.Context) error) {
+	if g.ctx.Err() != nil {
+		g.skip()
+		return
+	}
+	if g.sem != nil {
+		select {
+		case <-g.ctx.Done():
+			g.skip()
+			return
+		case g.sem <- struct{}{}:
+		}
+	}
+	g.start(f)
+}
+
+// TryGo runs the task in a new goroutine only if the limit of running
+// tasks is not reached and the context is not cancelled. It returns true
+// if the task was started.
+func (g *Group) TryGo(f func(context.Context) error) bool {
+	if g.ctx.Err() != nil {
+		return false
+	}
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+// Wait blocks until all started tasks finish, and cancels the context of
+// the group. It returns errors of all tasks joined together, the error
+// that cancelled the group goes first. If the group was cancelled by the
+// parent context, or if Go skipped tasks, the cause of the cancellation
+// goes first instead. It returns nil only if all tasks were started and
+// succeeded.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	errs := g.errs
+	if !g.byTask && (g.skipped || g.parent.Err() != nil) {
+		errs = append([]error{context.Cause(g.ctx)}, errs...)
+	}
+	g.cancel(nil)
+	return errors.Join(errs...)
+}
+
+func (g *Group) skip() {
+	g.mu.Lock()
+	g.skipped = true
+	g.mu.Unlock()
+}
+
+func (g *Group) start(f func(context.Context) error) {
+	g.wg.Go(func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+		}()
+		err := SafeCall(func() error { return f(g.ctx) })
+		if err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			if g.ctx.Err() == nil {
+				g.byTask = true
+				g.cancel(err)
+			}
+			g.mu.Unlock()
+		}
+	})
+}
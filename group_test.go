@@ -0,0 +1,155 @@
+package gnlib_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	assert := assert.New(t)
+	for _, limit := range []int{0, 1, 3} {
+		var cnt activeCounter
+		var count atomic.Int32
+		g := gnlib.NewGroup(context.Background(), limit)
+		for range 20 {
+			g.Go(func(context.Context) error {
+				defer cnt.enter()()
+				time.Sleep(time.Millisecond)
+				count.Add(1)
+				return nil
+			})
+		}
+		assert.Nil(g.Wait())
+		assert.Equal(int32(20), count.Load())
+		if limit > 0 {
+			assert.LessOrEqual(cnt.peak.Load(), int32(limit))
+		}
+		assert.NotNil(g.Context().Err())
+	}
+}
+
+func TestGroupError(t *testing.T) {
+	assert := assert.New(t)
+	errBad := errors.New("bad")
+	var started atomic.Int32
+	g := gnlib.NewGroup(context.Background(), 2)
+	for i := range 100 {
+		g.Go(func(ctx context.Context) error {
+			started.Add(1)
+			if i == 3 {
+				return errBad
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Millisecond):
+				return nil
+			}
+		})
+	}
+	err := g.Wait()
+	assert.ErrorIs(err, errBad)
+	assert.Less(started.Load(), int32(100))
+	assert.ErrorIs(context.Cause(g.Context()), errBad)
+	// the error that cancelled the group goes first
+	assert.True(errors.Is(err.(interface{ Unwrap() []error }).Unwrap()[0], errBad))
+}
+
+func TestGroupPanic(t *testing.T) {
+	assert := assert.New(t)
+	g := gnlib.NewGroup(context.Background(), 2)
+	g.Go(func(context.Context) error {
+		var m map[string]int
+		m["boom"] = 1
+		return nil
+	})
+	err := g.Wait()
+	var pErr *gnlib.PanicError
+	assert.ErrorAs(err, &pErr)
+	assert.Contains(string(pErr.Stack), "group_test.go")
+	var rtErr runtime.Error
+	assert.ErrorAs(err, &rtErr)
+}
+
+func TestGroupTryGo(t *testing.T) {
+	assert := assert.New(t)
+	g := gnlib.NewGroup(context.Background(), 1)
+	release := make(chan struct{})
+	assert.True(g.TryGo(func(context.Context) error {
+		<-release
+		return nil
+	}))
+	assert.False(g.TryGo(func(context.Context) error { return nil }))
+	close(release)
+	assert.Nil(g.Wait())
+	assert.False(g.TryGo(func(context.Context) error { return nil }))
+}
+
+func TestGroupCancel(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	g := gnlib.NewGroup(ctx, 1)
+	release := make(chan struct{})
+	g.Go(func(context.Context) error {
+		<-release
+		return nil
+	})
+	done := make(chan struct{})
+	go func() {
+		// blocks on the limit until the context is cancelled
+		g.Go(func(context.Context) error {
+			assert.Fail("task should not start")
+			return nil
+		})
+		close(done)
+	}()
+	cancel()
+	<-done
+	close(release)
+	assert.ErrorIs(g.Wait(), context.Canceled)
+}
+
+func TestGroupParentCancelled(t *testing.T) {
+	assert := assert.New(t)
+	errStop := errors.New("stop")
+	for _, limit := range []int{0, 2} {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(errStop)
+		g := gnlib.NewGroup(ctx, limit)
+		var ran bool
+		g.Go(func(context.Context) error {
+			ran = true
+			return nil
+		})
+		err := g.Wait()
+		assert.False(ran)
+		assert.ErrorIs(err, errStop)
+	}
+
+	// the parent is cancelled while tasks run
+	ctx, cancel := context.WithCancelCause(context.Background())
+	g := gnlib.NewGroup(ctx, 1)
+	errTask := errors.New("task")
+	g.Go(func(ctx context.Context) error {
+		cancel(errStop)
+		<-ctx.Done()
+		return errTask
+	})
+	err := g.Wait()
+	assert.ErrorIs(err, errStop)
+	assert.ErrorIs(err, errTask)
+	assert.True(errors.Is(err.(interface{ Unwrap() []error }).Unwrap()[0], errStop))
+
+	// tasks skipped after Wait are reported by the next Wait
+	g = gnlib.NewGroup(context.Background(), 1)
+	assert.Nil(g.Wait())
+	g.Go(func(context.Context) error { return nil })
+	assert.ErrorIs(g.Wait(), context.Canceled)
+}
@@ -0,0 +1,145 @@
+package gnlib
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter. The bucket holds up to `burst`
+// tokens and is refilled with `rate` tokens per second. Every event takes
+// one token. It is safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  Clock
+}
+
+// NewLimiter creates a Limiter that allows `rate` events per second with
+// bursts of up to `burst` events. The bucket starts full. If rate is not
+// positive, events are not limited. If burst is less than 1, it is set
+// to 1. If clock is nil, SystemClock is used.
+//
+// Example:
+//
+//	lim := NewLimiter(10, 5, nil)
+//	for _, batch := range batches {
+//		if err := lim.Wait(ctx); err != nil { return err }
+//		send(batch)
+//	}
+func NewLimiter(rate float64, burst int, clock Clock) *Limiter {
+	if clock == nil {
+		clock = SystemClock
+	}
+	burst = max(burst, 1)
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+		clock:  clock,
+	}
+}
+
+// Allow takes a token and returns true if a token is available now.
+// Otherwise it returns false and takes nothing.
+func (l *Limiter) Allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or the context is cancelled.
+// In case of cancellation the token is given back and the cause of the
+// cancellation is returned.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
+	d := l.reserve()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return context.Cause(ctx)
+	case <-l.clock.After(d):
+		return nil
+	}
+}
+
+// reserve takes a token, possibly in advance, and returns how long
+// to wait until the token becomes available.
+func (l *Limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refill adds tokens for the time passed since the last refill.
+func (l *Limiter) refill() {
+	now := l.clock.Now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+}
+
+// Throttle passes items from the input channel to the output channel
+// not faster than the limiter allows. The output channel is closed when
+// the input channel is closed or when the context is cancelled.
+//
+// Example:
+//
+//	lim := NewLimiter(5, 1, nil)
+//	chunks := Throttle(ctx, lim, ChunkChannel(ctx, names, 1000))
+//	for chunk := range chunks { verify(chunk) }
+func Throttle[T any](ctx context.Context, l *Limiter, input <-chan T) <-chan T {
+	output := make(chan T)
+	go func() {
+		defer close(output)
+		for {
+			var v T
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-input:
+				if !ok {
+					return
+				}
+			}
+			if l.Wait(ctx) != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case output <- v:
+			}
+		}
+	}()
+	return output
+}
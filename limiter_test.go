@@ -0,0 +1,130 @@
+package gnlib_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	lim := gnlib.NewLimiter(2, 3, clock)
+
+	// burst
+	for range 3 {
+		assert.True(lim.Allow())
+	}
+	assert.False(lim.Allow())
+
+	clock.Advance(250 * time.Millisecond)
+	assert.False(lim.Allow())
+	clock.Advance(250 * time.Millisecond)
+	assert.True(lim.Allow())
+	assert.False(lim.Allow())
+
+	// bucket does not overflow
+	clock.Advance(time.Hour)
+	for range 3 {
+		assert.True(lim.Allow())
+	}
+	assert.False(lim.Allow())
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	assert := assert.New(t)
+	lim := gnlib.NewLimiter(0, 1, newFakeClock())
+	for range 100 {
+		assert.True(lim.Allow())
+		assert.Nil(lim.Wait(context.Background()))
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	lim := gnlib.NewLimiter(10, 1, clock)
+	ctx := context.Background()
+	assert.Nil(lim.Wait(ctx))
+
+	done := make(chan error)
+	go func() { done <- lim.Wait(ctx) }()
+	clock.WaitTimers(1)
+	clock.Advance(50 * time.Millisecond)
+	select {
+	case <-done:
+		assert.Fail("Wait returned too early")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(50 * time.Millisecond)
+	assert.Nil(<-done)
+	assert.False(lim.Allow())
+}
+
+func TestLimiterWaitCancel(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	lim := gnlib.NewLimiter(1, 1, clock)
+	assert.True(lim.Allow())
+
+	errStop := errors.New("stop")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	done := make(chan error)
+	go func() { done <- lim.Wait(ctx) }()
+	clock.WaitTimers(1)
+	cancel(errStop)
+	assert.ErrorIs(<-done, errStop)
+
+	// the reserved token was given back
+	clock.Advance(time.Second)
+	assert.True(lim.Allow())
+	assert.ErrorIs(lim.Wait(ctx), errStop)
+}
+
+func TestThrottle(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	lim := gnlib.NewLimiter(1, 2, clock)
+	input := make(chan int, 5)
+	for i := range 5 {
+		input <- i
+	}
+	close(input)
+
+	output := gnlib.Throttle(context.Background(), lim, input)
+	assert.Equal(0, <-output)
+	assert.Equal(1, <-output)
+	for i := 2; i < 5; i++ {
+		clock.WaitTimers(1)
+		select {
+		case <-output:
+			assert.Fail("item came before the limiter allowed it")
+		default:
+		}
+		clock.Advance(time.Second)
+		assert.Equal(i, <-output)
+	}
+	_, ok := <-output
+	assert.False(ok)
+}
+
+func TestThrottleCancel(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	lim := gnlib.NewLimiter(1, 1, newFakeClock())
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make(chan int, 3)
+	input <- 1
+	input <- 2
+	output := gnlib.Throttle(ctx, lim, input)
+	assert.Equal(1, <-output)
+	cancel()
+	for range output {
+	}
+	assertNoLeak(t, before)
+}
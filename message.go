@@ -0,0 +1,69 @@
+package gnlib
+
+// ANSI escape sequences used to colorize terminal output.
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+)
+
+// Error is an error that is able to provide a user-friendly message for
+// the terminal in addition to the usual error string. It is usually
+// created by embedding MessageBase into a custom error type. Use
+// errors.As to find it in a chain of wrapped errors.
+type Error interface {
+	error
+
+	// UserMessage returns a formatted, colorized message meant for the
+	// end user.
+	UserMessage() string
+}
+
+// MessageBase keeps a message template and its variables. It is meant to
+// be embedded into custom error types to make them satisfy the Error
+// interface.
+type MessageBase struct {
+	// Msg is a message template. It might contain fmt verbs and
+	// <title>, <warning> and <em> tags.
+	Msg string
+
+	// Vars are the values substituted into the fmt verbs of Msg.
+	Vars []any
+}
+
+// NewMessage creates a MessageBase out of a message template and its
+// variables.
+func NewMessage(msg string, vars []any) MessageBase {
+	return MessageBase{Msg: msg, Vars: vars}
+}
+
+// UserMessage returns the message with variables substituted and tags
+// converted into terminal colors.
+func (m MessageBase) UserMessage() string {
+	return FormatMessage(m.Msg, m.Vars)
+}
+
+// Render returns the message with variables substituted and tags
+// converted by the given renderer.
+func (m MessageBase) Render(r Renderer) string {
+	return RenderMessage(r, m.Msg, m.Vars)
+}
+
+// FormatMessage substitutes vars into the fmt verbs of msg and converts
+// tags into terminal colors:
+//
+//   - `<title>...</title>`: green
+//   - `<warning>...</warning>`: red
+//   - `<em>...</em>`: yellow
+//
+// Tags are processed before the substitution, so tags that happen to be
+// inside of variables are not interpreted. Use RenderMessage to produce
+// output for other targets.
+//
+// Example:
+//
+//	msg := FormatMessage("Processing <title>%s</title>", []any{"data.csv"})
+func FormatMessage(msg string, vars []any) string {
+	return RenderMessage(ANSIRenderer{}, msg, vars)
+}
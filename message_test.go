@@ -0,0 +1,59 @@
+package gnlib_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+type fileError struct {
+	error
+	gnlib.MessageBase
+}
+
+func TestFormatMessage(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, tmpl string
+		vars      []any
+		res       string
+	}{
+		{"plain", "hello", nil, "hello"},
+		{"percent no vars", "100%", nil, "100%"},
+		{"vars", "%s: %d", []any{"file", 42}, "file: 42"},
+		{"title", "<title>Title</title>", nil, "\033[32mTitle\033[0m"},
+		{"warning", "<warning>Bad</warning>", nil, "\033[31mBad\033[0m"},
+		{"em", "<em>%d</em> items", []any{3}, "\033[33m3\033[0m items"},
+		{"tags in vars", "%s", []any{"<em>x</em>"}, "<em>x</em>"},
+		{"unknown tag", "<b>x</b>", nil, "<b>x</b>"},
+	}
+
+	for _, v := range tests {
+		res := gnlib.FormatMessage(v.tmpl, v.vars)
+		assert.Equal(v.res, res, v.msg)
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	assert := assert.New(t)
+	base := gnlib.NewMessage(
+		"<warning>Could not process file '%s'</warning>",
+		[]any{"important.txt"},
+	)
+	err := fileError{
+		error:       errors.New("file processing failed"),
+		MessageBase: base,
+	}
+	expected := "\033[31mCould not process file 'important.txt'\033[0m"
+	assert.Equal(expected, err.UserMessage())
+	assert.Equal("file processing failed", err.Error())
+
+	wrapped := fmt.Errorf("operation failed: %w", err)
+	var gnErr gnlib.Error
+	assert.True(errors.As(wrapped, &gnErr))
+	assert.Equal(expected, gnErr.UserMessage())
+	assert.Equal("file processing failed", gnErr.Error())
+}
@@ -0,0 +1,268 @@
+package gnlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+)
+
+// OrderedMap is a map that remembers the insertion order of its keys.
+// Lookups, insertions and deletions take constant time. Storing a value
+// for an existing key does not change the key position.
+//
+// The zero value is an empty map ready to use. OrderedMap is not safe for
+// concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	m    map[K]*orderedEntry[K, V]
+	head *orderedEntry[K, V]
+	tail *orderedEntry[K, V]
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *orderedEntry[K, V]
+}
+
+// NewOrderedMap creates an empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{m: make(map[K]*orderedEntry[K, V])}
+}
+
+// Load returns the value stored for the key and true, or the zero value
+// and false if there is no such key.
+func (m *OrderedMap[K, V]) Load(k K) (V, bool) {
+	if e, ok := m.m[k]; ok {
+		return e.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Store sets the value for the key. A new key is added to the end.
+func (m *OrderedMap[K, V]) Store(k K, v V) {
+	if e, ok := m.m[k]; ok {
+		e.val = v
+		return
+	}
+	if m.m == nil {
+		m.m = make(map[K]*orderedEntry[K, V])
+	}
+	e := &orderedEntry[K, V]{key: k, val: v, prev: m.tail}
+	if m.tail == nil {
+		m.head = e
+	} else {
+		m.tail.next = e
+	}
+	m.tail = e
+	m.m[k] = e
+}
+
+// Has returns true if the key is present.
+func (m *OrderedMap[K, V]) Has(k K) bool {
+	_, ok := m.m[k]
+	return ok
+}
+
+// Del removes the key from the map.
+func (m *OrderedMap[K, V]) Del(k K) {
+	e, ok := m.m[k]
+	if !ok {
+		return
+	}
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		m.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	delete(m.m, k)
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.m)
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	res := make([]K, 0, len(m.m))
+	for e := m.head; e != nil; e = e.next {
+		res = append(res, e.key)
+	}
+	return res
+}
+
+// Values returns the values in insertion order of their keys.
+func (m *OrderedMap[K, V]) Values() []V {
+	res := make([]V, 0, len(m.m))
+	for e := m.head; e != nil; e = e.next {
+		res = append(res, e.val)
+	}
+	return res
+}
+
+// All returns an iterator over key-value pairs in insertion order.
+// Deleting the current key during the iteration is allowed.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.head; e != nil; {
+			next := e.next
+			if !yield(e.key, e.val) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaller interface and converts the map
+// into a JSON object with keys in insertion order. Keys must marshal
+// into JSON strings or numbers.
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	var i int
+	for e := m.head; e != nil; e = e.next {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+		key, err := json.Marshal(e.key)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(key) > 0 && key[0] == '"':
+			buf.Write(key)
+		case len(key) > 0 && (key[0] == '-' || (key[0] >= '0' && key[0] <= '9')):
+			buf.WriteByte('"')
+			buf.Write(key)
+			buf.WriteByte('"')
+		default:
+			return nil, fmt.Errorf("cannot use %s as a JSON object key", key)
+		}
+		buf.WriteByte(':')
+		val, err := json.Marshal(e.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaller interface and converts
+// a JSON object into the map keeping the order of its keys. JSON null
+// leaves the map unchanged.
+func (m *OrderedMap[K, V]) UnmarshalJSON(bs []byte) error {
+	if bytes.Equal(bytes.TrimSpace(bs), []byte("null")) {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot decode %s as an OrderedMap", bs)
+	}
+
+	*m = *NewOrderedMap[K, V]()
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		keyStr := tok.(string)
+		var k K
+		if err = decodeKey(keyStr, &k); err != nil {
+			return err
+		}
+		var v V
+		if err = dec.Decode(&v); err != nil {
+			return err
+		}
+		m.Store(k, v)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// decodeKey converts a JSON object key into a value. Keys are tried as
+// JSON strings first, and as JSON numbers after that.
+func decodeKey[K any](s string, k *K) error {
+	quoted, _ := json.Marshal(s)
+	if err := json.Unmarshal(quoted, k); err == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), k)
+}
+
+// OrderedSet is a set that remembers the insertion order of its
+// elements. Membership checks, insertions and deletions take constant
+// time. Adding an existing element does not change its position.
+//
+// The zero value is an empty set ready to use. OrderedSet is not safe for
+// concurrent use.
+type OrderedSet[T comparable] struct {
+	m OrderedMap[T, struct{}]
+}
+
+// NewOrderedSet creates an OrderedSet from the given values. Duplicates
+// are ignored, the first occurrence defines the position.
+func NewOrderedSet[T comparable](vals ...T) *OrderedSet[T] {
+	res := &OrderedSet[T]{}
+	for _, v := range vals {
+		res.Add(v)
+	}
+	return res
+}
+
+func (s *OrderedSet[T]) Add(v T)      { s.m.Store(v, struct{}{}) }
+func (s *OrderedSet[T]) Has(v T) bool { return s.m.Has(v) }
+func (s *OrderedSet[T]) Del(v T)      { s.m.Del(v) }
+func (s *OrderedSet[T]) Len() int     { return s.m.Len() }
+
+// Items returns elements of the set in insertion order.
+func (s *OrderedSet[T]) Items() []T {
+	return s.m.Keys()
+}
+
+// All returns an iterator over elements of the set in insertion order.
+func (s *OrderedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for k := range s.m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaller interface and converts the set
+// into a JSON array in insertion order.
+func (s OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Items())
+}
+
+// UnmarshalJSON implements json.Unmarshaller interface and converts
+// a JSON array into the set keeping the order of the first occurrences.
+// JSON null leaves the set unchanged.
+func (s *OrderedSet[T]) UnmarshalJSON(bs []byte) error {
+	if bytes.Equal(bytes.TrimSpace(bs), []byte("null")) {
+		return nil
+	}
+	var items []T
+	if err := json.Unmarshal(bs, &items); err != nil {
+		return err
+	}
+	*s = *NewOrderedSet(items...)
+	return nil
+}
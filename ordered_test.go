@@ -0,0 +1,207 @@
+package gnlib_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewOrderedMap[string, int]()
+	m.Store("c", 1)
+	m.Store("a", 2)
+	m.Store("b", 3)
+	m.Store("a", 20) // keeps position
+
+	assert.Equal(3, m.Len())
+	assert.Equal([]string{"c", "a", "b"}, m.Keys())
+	assert.Equal([]int{1, 20, 3}, m.Values())
+	v, ok := m.Load("a")
+	assert.True(ok)
+	assert.Equal(20, v)
+	_, ok = m.Load("z")
+	assert.False(ok)
+	assert.True(m.Has("b"))
+}
+
+func TestOrderedMap_Del(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg string
+		del []int
+		res []int
+	}{
+		{"head", []int{1}, []int{2, 3, 4}},
+		{"middle", []int{2}, []int{1, 3, 4}},
+		{"tail", []int{4}, []int{1, 2, 3}},
+		{"missing", []int{10}, []int{1, 2, 3, 4}},
+		{"all", []int{1, 2, 3, 4}, []int{}},
+		{"reverse", []int{4, 3, 2, 1}, []int{}},
+	}
+
+	for _, v := range tests {
+		m := gnlib.NewOrderedMap[int, bool]()
+		for i := 1; i <= 4; i++ {
+			m.Store(i, true)
+		}
+		for _, d := range v.del {
+			m.Del(d)
+		}
+		assert.Equal(v.res, m.Keys(), v.msg)
+		assert.Equal(len(v.res), m.Len(), v.msg)
+		m.Store(5, true)
+		assert.Equal(append(v.res, 5), m.Keys(), v.msg)
+	}
+}
+
+func TestOrderedMap_All(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewOrderedMap[int, string]()
+	m.Store(3, "c")
+	m.Store(1, "a")
+	m.Store(2, "b")
+
+	var keys []int
+	var vals []string
+	for k, v := range m.All() {
+		keys = append(keys, k)
+		vals = append(vals, v)
+		m.Del(k)
+	}
+	assert.Equal([]int{3, 1, 2}, keys)
+	assert.Equal([]string{"c", "a", "b"}, vals)
+	assert.Equal(0, m.Len())
+}
+
+func TestOrderedMap_JSON(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewOrderedMap[string, []int]()
+	m.Store("zeta", []int{1})
+	m.Store("alpha", []int{2, 3})
+	m.Store("beta", nil)
+
+	exp := `{"zeta":[1],"alpha":[2,3],"beta":null}`
+	res, err := json.Marshal(m)
+	assert.Nil(err)
+	assert.Equal(exp, string(res))
+
+	res, err = gnfmt.GNjson{}.Encode(m)
+	assert.Nil(err)
+	assert.Equal(exp, string(res))
+
+	m2 := gnlib.NewOrderedMap[string, []int]()
+	err = json.Unmarshal(res, m2)
+	assert.Nil(err)
+	assert.Equal([]string{"zeta", "alpha", "beta"}, m2.Keys())
+	assert.Equal([][]int{{1}, {2, 3}, nil}, m2.Values())
+
+	m3 := gnlib.NewOrderedMap[int, string]()
+	m3.Store(10, "a")
+	m3.Store(-2, "b")
+	res, err = json.Marshal(m3)
+	assert.Nil(err)
+	assert.Equal(`{"10":"a","-2":"b"}`, string(res))
+	m4 := gnlib.NewOrderedMap[int, string]()
+	err = json.Unmarshal(res, m4)
+	assert.Nil(err)
+	assert.Equal([]int{10, -2}, m4.Keys())
+
+	err = json.Unmarshal([]byte(`null`), m4)
+	assert.Nil(err)
+	assert.Equal([]int{10, -2}, m4.Keys())
+
+	err = json.Unmarshal([]byte(`[1,2]`), m4)
+	assert.NotNil(err)
+	err = json.Unmarshal([]byte(`{"x":"a"}`), m4)
+	assert.NotNil(err)
+
+	type point struct{ X int }
+	m5 := gnlib.NewOrderedMap[point, int]()
+	m5.Store(point{1}, 1)
+	_, err = json.Marshal(m5)
+	assert.NotNil(err)
+}
+
+func TestOrderedSet(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewOrderedSet("Bubo bubo", "Puma concolor", "Bubo bubo")
+	s.Add("Aus bus")
+	s.Add("Puma concolor")
+	assert.Equal(3, s.Len())
+	assert.True(s.Has("Aus bus"))
+	assert.Equal([]string{"Bubo bubo", "Puma concolor", "Aus bus"}, s.Items())
+
+	s.Del("Puma concolor")
+	assert.False(s.Has("Puma concolor"))
+	var res []string
+	for v := range s.All() {
+		res = append(res, v)
+	}
+	assert.Equal([]string{"Bubo bubo", "Aus bus"}, res)
+}
+
+func TestOrderedSet_JSON(t *testing.T) {
+	assert := assert.New(t)
+	type data struct {
+		DataSources *gnlib.OrderedSet[int] `json:"dataSources"`
+	}
+	d := data{DataSources: gnlib.NewOrderedSet(11, 1, 9, 1)}
+	res, err := json.Marshal(d)
+	assert.Nil(err)
+	assert.Equal(`{"dataSources":[11,1,9]}`, string(res))
+
+	var d2 data
+	err = json.Unmarshal([]byte(`{"dataSources":[5,3,5,4]}`), &d2)
+	assert.Nil(err)
+	assert.Equal([]int{5, 3, 4}, d2.DataSources.Items())
+
+	err = json.Unmarshal([]byte(`{"dataSources":{"a":1}}`), &d2)
+	assert.NotNil(err)
+}
+
+func TestOrderedZero(t *testing.T) {
+	assert := assert.New(t)
+	var m gnlib.OrderedMap[string, int]
+	assert.Equal(0, m.Len())
+	m.Del("a")
+	m.Store("b", 1)
+	m.Store("a", 2)
+	assert.Equal([]string{"b", "a"}, m.Keys())
+
+	var s gnlib.OrderedSet[int]
+	assert.False(s.Has(1))
+	s.Add(3)
+	s.Add(1)
+	assert.Equal([]int{3, 1}, s.Items())
+}
+
+func TestOrderedValueFieldJSON(t *testing.T) {
+	assert := assert.New(t)
+	type data struct {
+		Names gnlib.OrderedMap[string, int] `json:"names"`
+		S     gnlib.OrderedSet[int]         `json:"s"`
+	}
+	src := `{"names":{"b":1,"a":2},"s":[3,1]}`
+	var d data
+	err := json.Unmarshal([]byte(src), &d)
+	assert.Nil(err)
+	assert.Equal([]string{"b", "a"}, d.Names.Keys())
+	assert.Equal([]int{3, 1}, d.S.Items())
+
+	res, err := json.Marshal(d)
+	assert.Nil(err)
+	assert.Equal(src, string(res))
+
+	err = json.Unmarshal([]byte(`{"names":null,"s":null}`), &d)
+	assert.Nil(err)
+	assert.Equal(2, d.Names.Len())
+	assert.Equal(2, d.S.Len())
+
+	res, err = json.Marshal(data{})
+	assert.Nil(err)
+	assert.Equal(`{"names":{},"s":[]}`, string(res))
+}
@@ -0,0 +1,137 @@
+package gnlib
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMap applies a function to each element of a slice using up to
+// `workers` goroutines and returns results in the same order as the
+// input. It stops at the first error, cancels the context given to the
+// other calls and returns the error wrapped into IndexError. If the
+// context is cancelled, ParallelMap stops and returns the cause of the
+// cancellation. Panics in f are recovered and returned as PanicError.
+//
+// Parameters:
+//   - `ctx`: Context for cancellation.
+//   - `s`: Input slice.
+//   - `workers`: Max number of concurrent calls of f, at least 1.
+//   - `f`: Function to apply to each element.
+//
+// Example:
+//
+//	res, err := ParallelMap(ctx, names, 8,
+//		func(ctx context.Context, name string) (Parsed, error) {
+//			return parse(name)
+//		},
+//	)
+func ParallelMap[T any, U any](
+	ctx context.Context,
+	s []T,
+	workers int,
+	f func(context.Context, T) (U, error),
+) ([]U, error) {
+	res, _, err := parallelMap(ctx, s, workers, f, true)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ParallelMapAll is similar to ParallelMap, but it does not stop on
+// errors. It returns all results, where elements that failed have zero
+// values, and all errors aggregated into BatchError. It still stops on
+// cancellation of the context, and joins the cancellation cause with the
+// BatchError.
+func ParallelMapAll[T any, U any](
+	ctx context.Context,
+	s []T,
+	workers int,
+	f func(context.Context, T) (U, error),
+) ([]U, error) {
+	res, errs, err := parallelMap(ctx, s, workers, f, false)
+	batchErr := newBatchError(errs)
+	switch {
+	case err == nil:
+		return res, batchErr
+	case batchErr == nil:
+		return res, err
+	default:
+		return res, errors.Join(batchErr, err)
+	}
+}
+
+// parallelMap processes elements of s concurrently. It returns results
+// and errors of f sorted by index. In fail-fast mode the first error of f
+// is returned as the last value. If processing was stopped by the parent
+// context, the last value is the cause of the cancellation.
+func parallelMap[T any, U any](
+	ctx context.Context,
+	s []T,
+	workers int,
+	f func(context.Context, T) (U, error),
+	failFast bool,
+) ([]U, []*IndexError, error) {
+	workers = max(1, min(workers, len(s)))
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	res := make([]U, len(s))
+	errs := make([]*IndexError, len(s))
+	var failed atomic.Bool
+
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range idx {
+				var v U
+				err := SafeCall(func() (err error) {
+					v, err = f(ctx, s[i])
+					return err
+				})
+				if err == nil {
+					res[i] = v
+					continue
+				}
+				errs[i] = &IndexError{Index: i, Err: err}
+				if failFast {
+					failed.Store(true)
+					cancel(errs[i])
+				}
+			}
+		})
+	}
+
+	var stopped bool
+loop:
+	for i := range s {
+		select {
+		case <-ctx.Done():
+			stopped = true
+			break loop
+		case idx <- i:
+		}
+	}
+	close(idx)
+	wg.Wait()
+
+	if failed.Load() {
+		// the first error is the one that cancelled the context
+		return res, nil, context.Cause(ctx)
+	}
+	var ctxErr error
+	if stopped {
+		ctxErr = context.Cause(ctx)
+	}
+
+	var resErrs []*IndexError
+	for _, err := range errs {
+		if err != nil {
+			resErrs = append(resErrs, err)
+		}
+	}
+	return res, resErrs, ctxErr
+}
@@ -0,0 +1,157 @@
+package gnlib_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// activeCounter tracks the largest number of calls running at the
+// same time.
+type activeCounter struct {
+	active, peak atomic.Int32
+}
+
+// enter registers a running call. The returned function unregisters it.
+func (c *activeCounter) enter() func() {
+	n := c.active.Add(1)
+	for {
+		m := c.peak.Load()
+		if n <= m || c.peak.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	return func() { c.active.Add(-1) }
+}
+
+func TestParallelMap(t *testing.T) {
+	assert := assert.New(t)
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+	var cnt activeCounter
+	f := func(_ context.Context, i int) (string, error) {
+		defer cnt.enter()()
+		time.Sleep(time.Duration(100-i) * time.Microsecond)
+		return strconv.Itoa(i), nil
+	}
+
+	for _, workers := range []int{-1, 0, 1, 4, 200} {
+		cnt.peak.Store(0)
+		res, err := gnlib.ParallelMap(context.Background(), input, workers, f)
+		assert.Nil(err)
+		assert.Equal(gnlib.Map(input, strconv.Itoa), res)
+		assert.LessOrEqual(cnt.peak.Load(), int32(max(workers, 1)))
+	}
+
+	res, err := gnlib.ParallelMap(context.Background(), []int{}, 4, f)
+	assert.Nil(err)
+	assert.Empty(res)
+}
+
+func TestParallelMapError(t *testing.T) {
+	assert := assert.New(t)
+	input := make([]int, 1000)
+	errBad := errors.New("bad")
+	var calls atomic.Int32
+	f := func(ctx context.Context, i int) (int, error) {
+		calls.Add(1)
+		if i == 10 {
+			return 0, errBad
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+		return i, nil
+	}
+	for i := range input {
+		input[i] = i
+	}
+
+	res, err := gnlib.ParallelMap(context.Background(), input, 4, f)
+	assert.Nil(res)
+	assert.ErrorIs(err, errBad)
+	var idxErr *gnlib.IndexError
+	assert.ErrorAs(err, &idxErr)
+	assert.Equal(10, idxErr.Index)
+	assert.Less(calls.Load(), int32(len(input)))
+}
+
+func TestParallelMapAll(t *testing.T) {
+	assert := assert.New(t)
+	errOdd := errors.New("odd")
+	f := func(_ context.Context, i int) (int, error) {
+		if i%2 == 1 {
+			return 0, errOdd
+		}
+		return i * 10, nil
+	}
+
+	res, err := gnlib.ParallelMapAll(context.Background(), []int{0, 1, 2, 3, 4}, 3, f)
+	assert.Equal([]int{0, 0, 20, 0, 40}, res)
+	assert.ErrorIs(err, errOdd)
+	assert.Equal("index 1: odd\nindex 3: odd", err.Error())
+	var batchErr *gnlib.BatchError
+	assert.ErrorAs(err, &batchErr)
+	assert.Equal([]int{1, 3}, batchErr.Indexes())
+
+	res, err = gnlib.ParallelMapAll(context.Background(), []int{0, 2}, 3, f)
+	assert.Nil(err)
+	assert.Equal([]int{0, 20}, res)
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	f := func(ctx context.Context, i int) (int, error) {
+		if i == 5 {
+			cancel()
+		}
+		if i > 5 {
+			<-ctx.Done()
+		}
+		return i, nil
+	}
+
+	res, err := gnlib.ParallelMap(ctx, input, 2, f)
+	assert.Nil(res)
+	assert.ErrorIs(err, context.Canceled)
+
+	res, err = gnlib.ParallelMapAll(ctx, input, 2, f)
+	assert.Len(res, 1000)
+	assert.ErrorIs(err, context.Canceled)
+}
+
+func TestParallelMapPanic(t *testing.T) {
+	assert := assert.New(t)
+	f := func(_ context.Context, s string) (int, error) {
+		if s == "" {
+			panic("empty string")
+		}
+		return len(s), nil
+	}
+
+	_, err := gnlib.ParallelMap(context.Background(), []string{"a", "", "b"}, 2, f)
+	var pErr *gnlib.PanicError
+	assert.ErrorAs(err, &pErr)
+	assert.Equal("empty string", pErr.Value)
+	assert.Contains(string(pErr.Stack), "parallel_test.go")
+	assert.Equal("index 1: panic: empty string", err.Error())
+
+	res, err := gnlib.ParallelMapAll(context.Background(), []string{"a", "", "bb"}, 2, f)
+	assert.Equal([]int{1, 0, 2}, res)
+	assert.ErrorAs(err, &pErr)
+}
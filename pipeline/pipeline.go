@@ -0,0 +1,264 @@
+// Package pipeline provides a builder for concurrent processing pipelines.
+// A pipeline consists of typed stages connected by channels. Every stage
+// runs several workers, but emits results in the order of its input, so
+// the order of items is preserved from the source to the sink.
+//
+// Example:
+//
+//	p := pipeline.New(ctx, pipeline.OptBuffer(1000))
+//	names := pipeline.FromSlice(p, nameStrings)
+//	fixed := pipeline.Add(p, names, pipeline.Stage[string, string]{
+//		Name:    "fix",
+//		Workers: 4,
+//		Func: func(_ context.Context, s string) (string, error) {
+//			return gnlib.FixUtf8(s), nil
+//		},
+//	})
+//	for name := range fixed {
+//		fmt.Println(name)
+//	}
+//	if err := p.Wait(); err != nil {
+//		log.Fatal(err)
+//	}
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gnames/gnlib"
+)
+
+// Pipeline keeps the shared state of stages: the context that is
+// cancelled on the first error, the size of buffers and the goroutines
+// of the stages.
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	buffer int
+
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+// Option is a function that modifies Pipeline settings.
+type Option func(*Pipeline)
+
+// OptBuffer sets the maximum number of items that a stage keeps in
+// flight, including items processed by workers and items waiting to be
+// emitted in order. When the limit is reached, the stage stops reading
+// its input, which creates backpressure. The buffer is never smaller
+// than the number of workers of a stage. The default is 64.
+func OptBuffer(n int) Option {
+	return func(p *Pipeline) {
+		if n > 0 {
+			p.buffer = n
+		}
+	}
+}
+
+// New creates a Pipeline. Cancelling the context stops all stages.
+func New(ctx context.Context, opts ...Option) *Pipeline {
+	res := &Pipeline{parent: ctx, buffer: 64}
+	res.ctx, res.cancel = context.WithCancelCause(ctx)
+	for _, opt := range opts {
+		opt(res)
+	}
+	return res
+}
+
+// Context returns the context of the pipeline. It is cancelled on the
+// first error, on Stop, or when the parent context is cancelled. Use it
+// for stages that are created outside of the package, for example for
+// gnlib.Chunker.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Stop cancels the pipeline. It is needed when a consumer stops reading
+// the output before it is closed.
+func (p *Pipeline) Stop() {
+	p.cancel(nil)
+}
+
+// Wait waits for all stages to finish. It returns the first error of a
+// stage, or the cancellation cause of the parent context. It returns nil
+// if the pipeline finished successfully or was stopped by Stop.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel(nil)
+	if p.err != nil {
+		return p.err
+	}
+	if p.parent.Err() != nil {
+		return context.Cause(p.parent)
+	}
+	return nil
+}
+
+// fail saves the first error and stops the pipeline. Errors that happen
+// after the pipeline is cancelled are ignored, they are usually caused by
+// the cancellation.
+func (p *Pipeline) fail(err error) {
+	if p.ctx.Err() != nil {
+		return
+	}
+	p.once.Do(func() {
+		p.err = err
+		p.cancel(err)
+	})
+}
+
+// StageError is an error returned by a function of a stage.
+type StageError struct {
+	// Stage is the name of the stage.
+	Stage string
+
+	// Seq is the sequence number of the item in the stage input.
+	Seq int
+
+	// Err is the original error.
+	Err error
+}
+
+// Error implements error interface.
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %q, item %d: %s", e.Stage, e.Seq, e.Err)
+}
+
+// Unwrap returns the original error.
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Stage describes one step of a pipeline.
+type Stage[In any, Out any] struct {
+	// Name of the stage is used in errors.
+	Name string
+
+	// Workers is the number of goroutines that run Func. The default is 1.
+	Workers int
+
+	// Func processes one item. Its panics are converted to
+	// gnlib.PanicError.
+	Func func(context.Context, In) (Out, error)
+}
+
+// FromSlice creates a source channel with the items of a slice.
+func FromSlice[T any](p *Pipeline, items []T) <-chan T {
+	out := make(chan T)
+	p.wg.Go(func() {
+		defer close(out)
+		for _, v := range items {
+			select {
+			case <-p.ctx.Done():
+				return
+			case out <- v:
+			}
+		}
+	})
+	return out
+}
+
+type job[T any] struct {
+	seq int
+	val T
+}
+
+// Add starts a stage that reads the input channel and returns the
+// output channel of the stage. Results are sent in the order of the
+// input. The output channel is closed when the input is exhausted, or
+// when the pipeline is cancelled.
+func Add[In any, Out any](
+	p *Pipeline,
+	input <-chan In,
+	s Stage[In, Out],
+) <-chan Out {
+	workers := max(1, s.Workers)
+	window := max(p.buffer, workers)
+	ctx := p.ctx
+
+	out := make(chan Out)
+	jobs := make(chan job[In])
+	results := make(chan job[Out], window)
+	// slots limits the number of items in flight
+	slots := make(chan struct{}, window)
+
+	p.wg.Go(func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+			var v In
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-input:
+				if !ok {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job[In]{seq: seq, val: v}:
+			}
+		}
+	})
+
+	var workersWG sync.WaitGroup
+	for range workers {
+		workersWG.Go(func() {
+			for j := range jobs {
+				var res Out
+				err := gnlib.SafeCall(func() (err error) {
+					res, err = s.Func(ctx, j.val)
+					return err
+				})
+				if err != nil {
+					p.fail(&StageError{Stage: s.Name, Seq: j.seq, Err: err})
+					continue
+				}
+				select {
+				case <-ctx.Done():
+				case results <- job[Out]{seq: j.seq, val: res}:
+				}
+			}
+		})
+	}
+	p.wg.Go(func() {
+		workersWG.Wait()
+		close(results)
+	})
+
+	p.wg.Go(func() {
+		defer close(out)
+		pending := make(map[int]Out)
+		var next int
+		for r := range results {
+			pending[r.seq] = r.val
+			for {
+				v, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+				<-slots
+				next++
+			}
+		}
+	})
+	return out
+}
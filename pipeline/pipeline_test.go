@@ -0,0 +1,201 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/gnames/gnlib/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func jitter() {
+	time.Sleep(time.Duration(rand.IntN(200)) * time.Microsecond)
+}
+
+func TestPipeline(t *testing.T) {
+	assert := assert.New(t)
+	input := make([]int, 500)
+	for i := range input {
+		input[i] = i
+	}
+
+	p := pipeline.New(context.Background(), pipeline.OptBuffer(16))
+	src := pipeline.FromSlice(p, input)
+	strs := pipeline.Add(p, src, pipeline.Stage[int, string]{
+		Name:    "itoa",
+		Workers: 8,
+		Func: func(_ context.Context, i int) (string, error) {
+			jitter()
+			return strconv.Itoa(i), nil
+		},
+	})
+	chunks, _ := gnlib.Chunker[string]{Size: 50}.Run(p.Context(), strs)
+	joined := pipeline.Add(p, chunks, pipeline.Stage[[]string, string]{
+		Name:    "join",
+		Workers: 3,
+		Func: func(_ context.Context, s []string) (string, error) {
+			jitter()
+			return strings.Join(s, ","), nil
+		},
+	})
+
+	var res []string
+	for s := range joined {
+		res = append(res, s)
+	}
+	assert.Nil(p.Wait())
+	assert.Len(res, 10)
+	all := strings.Split(strings.Join(res, ","), ",")
+	assert.Equal(gnlib.Map(input, strconv.Itoa), all)
+}
+
+func TestPipelineBuffer(t *testing.T) {
+	assert := assert.New(t)
+	var started atomic.Int32
+	input := make([]int, 200)
+	p := pipeline.New(context.Background(), pipeline.OptBuffer(5))
+	src := pipeline.FromSlice(p, input)
+	res := pipeline.Add(p, src, pipeline.Stage[int, int]{
+		Workers: 3,
+		Func: func(_ context.Context, i int) (int, error) {
+			started.Add(1)
+			return i, nil
+		},
+	})
+	var count int
+	for range res {
+		// slow consumer
+		time.Sleep(100 * time.Microsecond)
+		// items started, but not consumed yet, including this one
+		assert.LessOrEqual(started.Load()-int32(count), int32(5+1))
+		count++
+	}
+	assert.Nil(p.Wait())
+	assert.Equal(200, count)
+}
+
+func TestPipelineError(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	input := make([]int, 10_000)
+	for i := range input {
+		input[i] = i
+	}
+	errBad := errors.New("bad")
+	var calls atomic.Int32
+
+	p := pipeline.New(context.Background())
+	src := pipeline.FromSlice(p, input)
+	s1 := pipeline.Add(p, src, pipeline.Stage[int, int]{
+		Name:    "check",
+		Workers: 4,
+		Func: func(_ context.Context, i int) (int, error) {
+			calls.Add(1)
+			if i == 100 {
+				return 0, errBad
+			}
+			return i, nil
+		},
+	})
+	s2 := pipeline.Add(p, s1, pipeline.Stage[int, int]{
+		Name:    "double",
+		Workers: 2,
+		Func: func(_ context.Context, i int) (int, error) {
+			return i * 2, nil
+		},
+	})
+	var count int
+	for range s2 {
+		count++
+	}
+	err := p.Wait()
+	assert.ErrorIs(err, errBad)
+	var sErr *pipeline.StageError
+	assert.ErrorAs(err, &sErr)
+	assert.Equal("check", sErr.Stage)
+	assert.Equal(100, sErr.Seq)
+	assert.Equal(`stage "check", item 100: bad`, err.Error())
+	assert.LessOrEqual(count, 100)
+	assert.Less(calls.Load(), int32(len(input)))
+	assertNoLeak(t, before)
+}
+
+func TestPipelinePanic(t *testing.T) {
+	assert := assert.New(t)
+	p := pipeline.New(context.Background())
+	src := pipeline.FromSlice(p, []string{"a", "", "b"})
+	res := pipeline.Add(p, src, pipeline.Stage[string, byte]{
+		Name: "first",
+		Func: func(_ context.Context, s string) (byte, error) {
+			return s[0], nil
+		},
+	})
+	for range res {
+	}
+	err := p.Wait()
+	var pErr *gnlib.PanicError
+	assert.ErrorAs(err, &pErr)
+	var sErr *pipeline.StageError
+	assert.ErrorAs(err, &sErr)
+	assert.Equal(1, sErr.Seq)
+}
+
+func TestPipelineCancel(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	p := pipeline.New(ctx)
+	src := pipeline.FromSlice(p, make([]int, 1000))
+	res := pipeline.Add(p, src, pipeline.Stage[int, int]{
+		Workers: 4,
+		Func: func(ctx context.Context, i int) (int, error) {
+			return i, ctx.Err()
+		},
+	})
+	<-res
+	cancel()
+	for range res {
+	}
+	assert.ErrorIs(p.Wait(), context.Canceled)
+	assertNoLeak(t, before)
+}
+
+func TestPipelineStop(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+	p := pipeline.New(context.Background())
+	src := pipeline.FromSlice(p, make([]int, 1000))
+	res := pipeline.Add(p, src, pipeline.Stage[int, int]{
+		Workers: 4,
+		Func: func(ctx context.Context, i int) (int, error) {
+			return i, ctx.Err()
+		},
+	})
+	<-res
+	<-res
+	// consumer does not read the rest of the output
+	p.Stop()
+	assert.Nil(p.Wait())
+	assertNoLeak(t, before)
+}
+
+// assertNoLeak checks that the number of goroutines returns to the given
+// value.
+func assertNoLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines leaked: %d > %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
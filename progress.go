@@ -0,0 +1,195 @@
+package gnlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Progress is a snapshot of a job progress.
+type Progress struct {
+	// Items is the number of processed items.
+	Items int
+
+	// Chunks is the number of values that passed through a channel. It
+	// differs from Items when every value is a chunk of items.
+	Chunks int
+
+	// Total is the expected number of items, 0 if it is unknown.
+	Total int
+
+	// Elapsed is the time since the start of the job.
+	Elapsed time.Duration
+
+	// Rate is the number of items processed per second.
+	Rate float64
+
+	// ETA is the estimated time until the job is finished. It is 0 if
+	// Total is unknown.
+	ETA time.Duration
+
+	// Done is true when the input channel is closed.
+	Done bool
+}
+
+// Percent returns the percentage of processed items, or -1 if Total is
+// unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Items) / float64(p.Total) * 100
+}
+
+// Message creates a MessageBase that describes the progress.
+func (p Progress) Message() MessageBase {
+	msg := "Processed <em>%d</em> items"
+	vars := []any{p.Items}
+	if p.Total > 0 {
+		msg = "Processed <em>%d</em> of <em>%d</em> items (%.1f%%)"
+		vars = append(vars, p.Total, p.Percent())
+	}
+	msg += " in <title>%s</title>, %.0f items/sec"
+	vars = append(vars, p.Elapsed.Round(time.Second), p.Rate)
+	if p.Total > 0 && !p.Done {
+		msg += ", ETA <title>%s</title>"
+		vars = append(vars, p.ETA.Round(time.Second))
+	}
+	return NewMessage(msg, vars)
+}
+
+// ProgressTap passes values through a channel unchanged, counts them and
+// periodically reports the progress.
+//
+// Example:
+//
+//	tap := ProgressTap[[]string]{
+//		Total:      len(names),
+//		Size:       func(c []string) int { return len(c) },
+//		OnProgress: ProgressTerm(os.Stderr, nil),
+//	}
+//	chunks = tap.Run(ctx, chunks)
+type ProgressTap[T any] struct {
+	// Total is the expected number of items, 0 if it is unknown.
+	Total int
+
+	// Interval is the time between reports. The default is 1 second.
+	Interval time.Duration
+
+	// Size returns the number of items in a value, for example the length
+	// of a chunk. If it is nil, every value is one item.
+	Size func(T) int
+
+	// OnProgress is called every Interval, and once more when the input
+	// channel is closed. It is called from the goroutine that passes
+	// values, so it should return quickly.
+	OnProgress func(Progress)
+
+	// Clock is used for time measurements, the default is SystemClock.
+	Clock Clock
+}
+
+// Run starts passing values from the input channel to the returned
+// channel. The returned channel is closed when the input channel is
+// closed, or when the context is cancelled.
+func (t ProgressTap[T]) Run(ctx context.Context, input <-chan T) <-chan T {
+	clock := t.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	interval := t.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	output := make(chan T)
+	go func() {
+		defer close(output)
+		start := clock.Now()
+		p := Progress{Total: t.Total}
+		report := func() {
+			if t.OnProgress == nil {
+				return
+			}
+			p.Elapsed = clock.Now().Sub(start)
+			if secs := p.Elapsed.Seconds(); secs > 0 {
+				p.Rate = float64(p.Items) / secs
+			}
+			if t.Total > 0 && p.Rate > 0 && p.Items < t.Total {
+				secs := float64(t.Total-p.Items) / p.Rate
+				p.ETA = time.Duration(secs * float64(time.Second))
+			} else {
+				p.ETA = 0
+			}
+			t.OnProgress(p)
+		}
+
+		tick := clock.After(interval)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				report()
+				tick = clock.After(interval)
+			case v, ok := <-input:
+				if !ok {
+					p.Done = true
+					report()
+					return
+				}
+				p.Chunks++
+				if t.Size == nil {
+					p.Items++
+				} else {
+					p.Items += t.Size(v)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case output <- v:
+				}
+			}
+		}
+	}()
+	return output
+}
+
+// ProgressTerm returns a progress callback for terminals. It keeps the
+// report on one line, rewriting it with every call. If w is not
+// a terminal (for example the output is redirected to a file), every
+// report is written as a separate line without terminal control
+// sequences. Tags of the report are converted by the renderer. If the
+// renderer is nil, it is chosen by NewTermRenderer when w is a file, or
+// PlainRenderer is used otherwise.
+func ProgressTerm(w io.Writer, r Renderer) func(Progress) {
+	f, _ := w.(*os.File)
+	if r == nil {
+		r = PlainRenderer{}
+		if f != nil {
+			r = NewTermRenderer(f)
+		}
+	}
+	if !isTerminal(f) {
+		return func(p Progress) {
+			fmt.Fprintln(w, p.Message().Render(r))
+		}
+	}
+	return func(p Progress) {
+		// \r returns to the start of the line, \033[K clears it.
+		fmt.Fprint(w, "\r\033[K"+p.Message().Render(r))
+		if p.Done {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// ProgressLog returns a progress callback that writes every report as
+// a separate plain text line. It is suitable for log files.
+func ProgressLog(w io.Writer) func(Progress) {
+	return func(p Progress) {
+		fmt.Fprintln(w, p.Message().Render(PlainRenderer{}))
+	}
+}
@@ -0,0 +1,154 @@
+package gnlib_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressMessage(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg string
+		p   gnlib.Progress
+		res string
+	}{
+		{
+			"unknown total",
+			gnlib.Progress{Items: 500, Elapsed: 2 * time.Second, Rate: 250},
+			"Processed 500 items in 2s, 250 items/sec",
+		},
+		{
+			"known total",
+			gnlib.Progress{
+				Items: 500, Total: 2000, Elapsed: 2 * time.Second,
+				Rate: 250, ETA: 6 * time.Second,
+			},
+			"Processed 500 of 2000 items (25.0%) in 2s, 250 items/sec, ETA 6s",
+		},
+		{
+			"done",
+			gnlib.Progress{
+				Items: 2000, Total: 2000, Elapsed: 8 * time.Second,
+				Rate: 250, Done: true,
+			},
+			"Processed 2000 of 2000 items (100.0%) in 8s, 250 items/sec",
+		},
+	}
+
+	for _, v := range tests {
+		res := v.p.Message().Render(gnlib.PlainRenderer{})
+		assert.Equal(v.res, res, v.msg)
+	}
+	assert.Equal(-1.0, gnlib.Progress{Items: 5}.Percent())
+}
+
+func TestProgressTap(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	var mu sync.Mutex
+	var reports []gnlib.Progress
+	tap := gnlib.ProgressTap[[]int]{
+		Total:    10,
+		Interval: time.Second,
+		Size:     func(c []int) int { return len(c) },
+		Clock:    clock,
+		OnProgress: func(p gnlib.Progress) {
+			mu.Lock()
+			reports = append(reports, p)
+			mu.Unlock()
+		},
+	}
+	input := make(chan []int)
+	output := tap.Run(context.Background(), input)
+
+	go func() { input <- []int{1, 2, 3, 4} }()
+	assert.Equal([]int{1, 2, 3, 4}, <-output)
+	clock.WaitTimers(1)
+	clock.Advance(2 * time.Second)
+
+	go func() {
+		input <- []int{5}
+		close(input)
+	}()
+	assert.Equal([]int{5}, <-output)
+	for range output {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(reports, 2)
+	assert.Equal(gnlib.Progress{
+		Items: 4, Chunks: 1, Total: 10, Elapsed: 2 * time.Second,
+		Rate: 2, ETA: 3 * time.Second,
+	}, reports[0])
+	assert.Equal(gnlib.Progress{
+		Items: 5, Chunks: 2, Total: 10, Elapsed: 2 * time.Second,
+		Rate: 2.5, ETA: 2 * time.Second, Done: true,
+	}, reports[1])
+}
+
+func TestProgressTapNoSize(t *testing.T) {
+	assert := assert.New(t)
+	var last gnlib.Progress
+	tap := gnlib.ProgressTap[string]{
+		OnProgress: func(p gnlib.Progress) { last = p },
+	}
+	input := make(chan string, 3)
+	input <- "a"
+	input <- "b"
+	input <- "c"
+	close(input)
+	var res []string
+	for v := range tap.Run(context.Background(), input) {
+		res = append(res, v)
+	}
+	assert.Equal([]string{"a", "b", "c"}, res)
+	assert.Equal(3, last.Items)
+	assert.Equal(3, last.Chunks)
+	assert.True(last.Done)
+	assert.Equal(time.Duration(0), last.ETA)
+}
+
+func TestProgressOutput(t *testing.T) {
+	assert := assert.New(t)
+	p := gnlib.Progress{Items: 3, Elapsed: time.Second, Rate: 3}
+
+	var buf bytes.Buffer
+	f := gnlib.ProgressLog(&buf)
+	f(p)
+	p.Done = true
+	f(p)
+	line := "Processed 3 items in 1s, 3 items/sec\n"
+	assert.Equal(line+line, buf.String())
+
+	buf.Reset()
+	f = gnlib.ProgressTerm(&buf, nil)
+	p.Done = false
+	f(p)
+	p.Done = true
+	f(p)
+	// not a terminal, so no control sequences
+	assert.Equal(line+line, buf.String())
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "progress.log"))
+	assert.Nil(err)
+	defer file.Close()
+	f = gnlib.ProgressTerm(file, nil)
+	f(p)
+	bs, err := os.ReadFile(file.Name())
+	assert.Nil(err)
+	assert.Equal(line, string(bs))
+
+	buf.Reset()
+	f = gnlib.ProgressTerm(&buf, gnlib.ANSIRenderer{})
+	f(p)
+	assert.Contains(buf.String(), "\033[33m3\033[0m items")
+}
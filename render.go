@@ -0,0 +1,194 @@
+package gnlib
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Tags recognized in message templates.
+const (
+	// TagTitle marks a title or a highlighted name.
+	TagTitle = "title"
+
+	// TagWarning marks a warning or an error.
+	TagWarning = "warning"
+
+	// TagEm marks an emphasized value.
+	TagEm = "em"
+)
+
+var tagRegex = regexp.MustCompile(`</?(title|warning|em)>`)
+
+// Renderer converts message tags into the markup of a particular output
+// target, such as a terminal, a log file, a web page or a Markdown
+// document.
+type Renderer interface {
+	// Open returns the markup that starts a fragment marked by the tag.
+	Open(tag string) string
+
+	// Close returns the markup that ends a fragment marked by the tag.
+	Close(tag string) string
+
+	// Escape makes literal text safe for the target. It is applied to
+	// the text of a template and to the formatted values of variables.
+	Escape(s string) string
+}
+
+// ANSIRenderer renders tags as terminal colors: titles are green,
+// warnings are red and emphasized text is yellow.
+type ANSIRenderer struct{}
+
+// Open implements Renderer interface.
+func (ANSIRenderer) Open(tag string) string {
+	switch tag {
+	case TagTitle:
+		return colorGreen
+	case TagWarning:
+		return colorRed
+	case TagEm:
+		return colorYellow
+	default:
+		return ""
+	}
+}
+
+// Close implements Renderer interface.
+func (ANSIRenderer) Close(string) string { return colorReset }
+
+// Escape implements Renderer interface.
+func (ANSIRenderer) Escape(s string) string { return s }
+
+// PlainRenderer removes tags and leaves text intact. It is suitable for
+// log files and for terminals that do not support colors.
+type PlainRenderer struct{}
+
+// Open implements Renderer interface.
+func (PlainRenderer) Open(string) string { return "" }
+
+// Close implements Renderer interface.
+func (PlainRenderer) Close(string) string { return "" }
+
+// Escape implements Renderer interface.
+func (PlainRenderer) Escape(s string) string { return s }
+
+// HTMLRenderer converts tags into span elements with `gn-title`,
+// `gn-warning` and `gn-em` classes. Text and variables are HTML-escaped.
+type HTMLRenderer struct{}
+
+// Open implements Renderer interface.
+func (HTMLRenderer) Open(tag string) string {
+	return `<span class="gn-` + tag + `">`
+}
+
+// Close implements Renderer interface.
+func (HTMLRenderer) Close(string) string { return "</span>" }
+
+// Escape implements Renderer interface.
+func (HTMLRenderer) Escape(s string) string { return html.EscapeString(s) }
+
+// MarkdownRenderer converts titles and warnings into strong emphasis and
+// emphasized text into emphasis. Markdown control characters in text and
+// variables are escaped.
+type MarkdownRenderer struct{}
+
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`",
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+// Open implements Renderer interface.
+func (MarkdownRenderer) Open(tag string) string {
+	if tag == TagEm {
+		return "_"
+	}
+	return "**"
+}
+
+// Close implements Renderer interface.
+func (r MarkdownRenderer) Close(tag string) string { return r.Open(tag) }
+
+// Escape implements Renderer interface.
+func (MarkdownRenderer) Escape(s string) string { return mdEscaper.Replace(s) }
+
+// NewTermRenderer picks a renderer for the given output file. It returns
+// PlainRenderer if the NO_COLOR environment variable is set, or if the
+// file is not a terminal (for example when the output is piped or
+// redirected). Otherwise it returns ANSIRenderer.
+func NewTermRenderer(f *os.File) Renderer {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return PlainRenderer{}
+	}
+	if !isTerminal(f) {
+		return PlainRenderer{}
+	}
+	return ANSIRenderer{}
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// tagMark takes the place of tags while fmt.Sprintf formats a template,
+// so the tags can be found in the result.
+const tagMark = "\x00"
+
+// RenderMessage substitutes vars into the fmt verbs of msg and converts
+// tags into the markup provided by the renderer. Tags are processed
+// before the substitution, so tags inside of variables are not
+// interpreted. The text of the template and the formatted values of
+// variables are escaped by the renderer, the markup is not. Variables
+// are formatted by fmt.Sprintf, so verbs and their errors, such as
+// %!d(MISSING), are the same as in fmt.
+//
+// Example:
+//
+//	msg := RenderMessage(HTMLRenderer{}, "<em>%d</em> names", []any{5})
+//	// msg: <span class="gn-em">5</span> names
+func RenderMessage(r Renderer, msg string, vars []any) string {
+	texts, marks := splitTags(r, msg)
+	if len(vars) > 0 {
+		res := fmt.Sprintf(strings.Join(texts, tagMark), vars...)
+		parts := strings.Split(res, tagMark)
+		if len(parts) != len(marks)+1 {
+			// the mark came from the text or from variables, the markup
+			// is dropped to keep the text intact
+			return r.Escape(fmt.Sprintf(strings.Join(texts, ""), vars...))
+		}
+		texts = parts
+	}
+
+	var sb strings.Builder
+	for i, s := range texts {
+		if i > 0 {
+			sb.WriteString(marks[i-1])
+		}
+		sb.WriteString(r.Escape(s))
+	}
+	return sb.String()
+}
+
+// splitTags splits msg by tags. It returns the text between tags and
+// the markup of the renderer for every tag.
+func splitTags(r Renderer, msg string) ([]string, []string) {
+	var texts, marks []string
+	var start int
+	for _, loc := range tagRegex.FindAllStringSubmatchIndex(msg, -1) {
+		texts = append(texts, msg[start:loc[0]])
+		tag := msg[loc[2]:loc[3]]
+		if msg[loc[0]+1] == '/' {
+			marks = append(marks, r.Close(tag))
+		} else {
+			marks = append(marks, r.Open(tag))
+		}
+		start = loc[1]
+	}
+	return append(texts, msg[start:]), marks
+}
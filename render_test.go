@@ -0,0 +1,158 @@
+package gnlib_test
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMessage(t *testing.T) {
+	assert := assert.New(t)
+	tmpl := "<title>%s</title>: <em>%d</em> <warning>names</warning>"
+	vars := []any{"a<b>_c", 5}
+	tests := []struct {
+		msg string
+		r   gnlib.Renderer
+		res string
+	}{
+		{
+			"ansi", gnlib.ANSIRenderer{},
+			"\033[32ma<b>_c\033[0m: \033[33m5\033[0m \033[31mnames\033[0m",
+		},
+		{"plain", gnlib.PlainRenderer{}, "a<b>_c: 5 names"},
+		{
+			"html", gnlib.HTMLRenderer{},
+			`<span class="gn-title">a&lt;b&gt;_c</span>: ` +
+				`<span class="gn-em">5</span> <span class="gn-warning">names</span>`,
+		},
+		{"markdown", gnlib.MarkdownRenderer{}, `**a\<b\>\_c**: _5_ **names**`},
+	}
+
+	for _, v := range tests {
+		res := gnlib.RenderMessage(v.r, tmpl, vars)
+		assert.Equal(v.res, res, v.msg)
+	}
+}
+
+func TestRenderMessageEscape(t *testing.T) {
+	assert := assert.New(t)
+	res := gnlib.RenderMessage(gnlib.HTMLRenderer{}, "a & <em>%5.1f</em>", []any{3.14159})
+	assert.Equal(`a &amp; <span class="gn-em">  3.1</span>`, res)
+
+	res = gnlib.RenderMessage(gnlib.PlainRenderer{}, "<b>%q</b>", []any{"x"})
+	assert.Equal(`<b>"x"</b>`, res)
+}
+
+func TestMessageBaseRender(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewMessage("<warning>File '%s'</warning>", []any{"a.txt"})
+	assert.Equal("File 'a.txt'", m.Render(gnlib.PlainRenderer{}))
+	assert.Equal(
+		`<span class="gn-warning">File &#39;a.txt&#39;</span>`,
+		m.Render(gnlib.HTMLRenderer{}),
+	)
+}
+
+func TestNewTermRenderer(t *testing.T) {
+	assert := assert.New(t)
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	assert.Nil(err)
+	defer f.Close()
+	assert.Equal(gnlib.PlainRenderer{}, gnlib.NewTermRenderer(f))
+	assert.Equal(gnlib.PlainRenderer{}, gnlib.NewTermRenderer(nil))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(gnlib.PlainRenderer{}, gnlib.NewTermRenderer(os.Stdout))
+}
+
+func TestRenderMessageIndexes(t *testing.T) {
+	assert := assert.New(t)
+	tmpl := "在<title>%[2]s</title>中找到<em>%[1]d</em>个名称"
+	vars := []any{5, "a_b.txt"}
+	tests := []struct {
+		msg string
+		r   gnlib.Renderer
+		res string
+	}{
+		{"plain", gnlib.PlainRenderer{}, "在a_b.txt中找到5个名称"},
+		{"markdown", gnlib.MarkdownRenderer{}, `在**a\_b.txt**中找到_5_个名称`},
+		{
+			"html", gnlib.HTMLRenderer{},
+			`在<span class="gn-title">a_b.txt</span>中找到<span class="gn-em">5</span>个名称`,
+		},
+	}
+
+	for _, v := range tests {
+		res := gnlib.RenderMessage(v.r, tmpl, vars)
+		assert.Equal(v.res, res, v.msg)
+	}
+}
+
+// TestRenderMessageVerbs checks that for templates without tags
+// RenderMessage gives the escaped result of fmt.Sprintf.
+func TestRenderMessageVerbs(t *testing.T) {
+	assert := assert.New(t)
+	var p *int
+	tests := []struct {
+		tmpl string
+		vars []any
+	}{
+		{"%T", []any{5}},
+		{"%*d|", []any{5, 3}},
+		{"%-*d|", []any{5, 3}},
+		{"%.*f|%d", []any{2, 3.14159, 7}},
+		{"%[2]*[1]d|", []any{3, 5}},
+		{"%6.2f%%", []any{12.345}},
+		{"%[2]s %s %[1]s", []any{"a", "b", "c"}},
+		{"%p", []any{p}},
+		{"%d %d", []any{1}},
+		{"%d", []any{1, "x", nil}},
+		{"%[3]d", []any{1}},
+		{"%[x]d %d", []any{1, 2}},
+		{"%[0]d", []any{1}},
+		{"%!", []any{1}},
+		{"%", []any{1}},
+		{"%.", []any{1}},
+		{"%[]", []any{1}},
+		{"%[1]", []any{1}},
+		{"%-*[2]d|", []any{5, 3}},
+		{"%*d", []any{"x", 3}},
+		{"a_b %s", []any{"<i>*x*</i>"}},
+		{"%v %+v %#v %x % x %q", []any{[]int{1}, struct{ A int }{1}, "s", 255, "ab", "q"}},
+	}
+
+	for _, v := range tests {
+		exp := fmt.Sprintf(v.tmpl, v.vars...)
+		assert.Equal(exp, gnlib.FormatMessage(v.tmpl, v.vars), v.tmpl)
+		assert.Equal(exp, gnlib.RenderMessage(gnlib.PlainRenderer{}, v.tmpl, v.vars), v.tmpl)
+		assert.Equal(
+			html.EscapeString(exp),
+			gnlib.RenderMessage(gnlib.HTMLRenderer{}, v.tmpl, v.vars),
+			v.tmpl,
+		)
+		assert.Equal(
+			html.EscapeString(exp),
+			gnlib.RenderMessage(&gnlib.HTMLRenderer{}, v.tmpl, v.vars),
+			v.tmpl,
+		)
+		assert.Equal(
+			gnlib.MarkdownRenderer{}.Escape(exp),
+			gnlib.RenderMessage(gnlib.MarkdownRenderer{}, v.tmpl, v.vars),
+			v.tmpl,
+		)
+	}
+}
+
+func TestRenderMessageMark(t *testing.T) {
+	assert := assert.New(t)
+	// a variable that looks like a tag mark does not break the text
+	res := gnlib.RenderMessage(
+		gnlib.HTMLRenderer{}, "<em>%s</em> & %d", []any{"a\x00b", 5},
+	)
+	assert.Equal("a\x00b &amp; 5", res)
+}
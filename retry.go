@@ -0,0 +1,242 @@
+package gnlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry calls a function again when it fails with a transient error.
+// Delays between attempts grow exponentially up to MaxDelay, and every
+// delay is randomized with full jitter, so clients that failed at the
+// same time do not retry at the same time. The zero value makes
+// 3 attempts with 100ms base delay.
+//
+// Example:
+//
+//	r := Retry{Attempts: 5, MaxElapsed: time.Minute, Retryable: isTransient}
+//	err := r.Do(ctx, func(ctx context.Context) error {
+//		return sendBatch(ctx, names)
+//	})
+type Retry struct {
+	// Attempts is the maximum number of calls, including the first one.
+	// If it is less than 1, 3 attempts are made.
+	Attempts int
+
+	// BaseDelay is the maximum delay before the second attempt. The
+	// maximum delay doubles with every next attempt. If it is not
+	// positive, 100ms is used.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the maximum delay between attempts. If it is not
+	// positive, delays are not capped.
+	MaxDelay time.Duration
+
+	// MaxElapsed is the overall time limit of all attempts and delays.
+	// No retry is made if its delay would end after the limit, and the
+	// context of an attempt is cancelled when the limit is reached. If it
+	// is not positive, the time is limited by the context only.
+	MaxElapsed time.Duration
+
+	// Retryable, if set, decides if an error is transient and the call
+	// should be repeated. If it is not set, all errors are retryable.
+	// Context cancellation errors are never retried.
+	Retryable func(error) bool
+
+	// Clock provides the current time for MaxElapsed and timers for the
+	// default sleeper. If it is nil, SystemClock is used.
+	Clock Clock
+
+	// Sleep, if set, waits for the duration d or until the context is
+	// cancelled. It returns a non-nil error if the context was cancelled.
+	// If it is not set, the sleeper uses timers of the Clock.
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	// Jitter, if set, returns the actual delay for a maximum delay d.
+	// If it is not set, the delay is a random duration in [0, d].
+	Jitter func(d time.Duration) time.Duration
+}
+
+// RetryError is returned by Retry when all attempts failed, or when
+// MaxElapsed time ran out.
+type RetryError struct {
+	// Attempts is the number of calls made.
+	Attempts int
+
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Error implements error interface.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempts: %s", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Do calls f until it succeeds, returns a non-retryable error, or the
+// attempts or time run out. A non-retryable error is returned as is.
+// If attempts or time run out, a *RetryError is returned. If the context
+// is cancelled, the cause of the cancellation is returned joined with
+// the last error.
+func (r Retry) Do(ctx context.Context, f func(context.Context) error) error {
+	_, err := RetryValue(ctx, r, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	})
+	return err
+}
+
+// RetryValue is the same as Retry.Do for functions that return a value.
+//
+// Example:
+//
+//	res, err := RetryValue(ctx, Retry{Attempts: 5}, func(ctx context.Context) ([]verifier.Name, error) {
+//		return client.Verify(ctx, names)
+//	})
+func RetryValue[T any](
+	ctx context.Context,
+	r Retry,
+	f func(context.Context) (T, error),
+) (T, error) {
+	r.setDefaults()
+	start := r.Clock.Now()
+	parent := ctx
+	if r.MaxElapsed > 0 {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = r.withTimeout(ctx, r.MaxElapsed)
+		defer cancel(nil)
+	}
+	// stopped returns an error if the context was cancelled.
+	stopped := func(attempts int, err error) error {
+		if parent.Err() != nil {
+			return errors.Join(context.Cause(parent), err)
+		}
+		if ctx.Err() != nil {
+			// MaxElapsed time ran out
+			return &RetryError{Attempts: attempts, Err: err}
+		}
+		return nil
+	}
+
+	var zero T
+	var err error
+	for attempt := 1; ; attempt++ {
+		if sErr := stopped(attempt-1, err); sErr != nil {
+			return zero, sErr
+		}
+
+		var res T
+		res, err = f(ctx)
+		if err == nil {
+			return res, nil
+		}
+		if sErr := stopped(attempt, err); sErr != nil {
+			return zero, sErr
+		}
+		if !r.retryable(err) {
+			return zero, err
+		}
+		if attempt >= r.Attempts {
+			return zero, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		d := r.Jitter(r.backoff(attempt))
+		if r.MaxElapsed > 0 && r.Clock.Now().Add(d).Sub(start) > r.MaxElapsed {
+			return zero, &RetryError{Attempts: attempt, Err: err}
+		}
+		if sleepErr := r.Sleep(ctx, d); sleepErr != nil {
+			if sErr := stopped(attempt, err); sErr != nil {
+				return zero, sErr
+			}
+			return zero, errors.Join(sleepErr, err)
+		}
+	}
+}
+
+// withTimeout returns a context that is cancelled after the duration d
+// of the Clock.
+func (r Retry) withTimeout(
+	ctx context.Context,
+	d time.Duration,
+) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timeout := r.Clock.After(d)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-timeout:
+			cancel(fmt.Errorf("retry time limit %s: %w", d, context.DeadlineExceeded))
+		}
+	}()
+	return ctx, cancel
+}
+
+func (r *Retry) setDefaults() {
+	if r.Attempts < 1 {
+		r.Attempts = 3
+	}
+	if r.BaseDelay <= 0 {
+		r.BaseDelay = 100 * time.Millisecond
+	}
+	if r.Clock == nil {
+		r.Clock = SystemClock
+	}
+	if r.Sleep == nil {
+		r.Sleep = r.clockSleep
+	}
+	if r.Jitter == nil {
+		r.Jitter = fullJitter
+	}
+}
+
+// backoff returns the maximum delay after the given attempt.
+func (r Retry) backoff(attempt int) time.Duration {
+	res := r.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if r.MaxDelay > 0 && res >= r.MaxDelay {
+			break
+		}
+		// avoid overflow for large number of attempts
+		if res > time.Duration(1<<62) {
+			break
+		}
+		res *= 2
+	}
+	if r.MaxDelay > 0 {
+		res = min(res, r.MaxDelay)
+	}
+	return res
+}
+
+func (r Retry) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return r.Retryable == nil || r.Retryable(err)
+}
+
+func (r Retry) clockSleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-r.Clock.After(d):
+		return nil
+	}
+}
+
+// fullJitter returns a random duration in [0, d].
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
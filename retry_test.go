@@ -0,0 +1,232 @@
+package gnlib_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepRecorder is a sleeper that moves a fake clock instead of sleeping.
+type sleepRecorder struct {
+	clock  *fakeClock
+	delays []time.Duration
+}
+
+func (s *sleepRecorder) Sleep(ctx context.Context, d time.Duration) error {
+	s.delays = append(s.delays, d)
+	s.clock.Advance(d)
+	return ctx.Err()
+}
+
+func noJitter(d time.Duration) time.Duration { return d }
+
+func TestRetry(t *testing.T) {
+	assert := assert.New(t)
+	errTemp := errors.New("temporary")
+	errFatal := errors.New("fatal")
+	tests := []struct {
+		msg      string
+		r        gnlib.Retry
+		errs     []error
+		calls    int
+		delays   []time.Duration
+		isRetErr bool
+		err      error
+	}{
+		{"ok", gnlib.Retry{}, nil, 1, nil, false, nil},
+		{
+			"ok after retries", gnlib.Retry{Attempts: 5},
+			[]error{errTemp, errTemp}, 3,
+			[]time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			false, nil,
+		},
+		{
+			"default attempts", gnlib.Retry{},
+			[]error{errTemp, errTemp, errTemp, errTemp}, 3,
+			[]time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			true, errTemp,
+		},
+		{
+			"capped", gnlib.Retry{Attempts: 6, BaseDelay: time.Second, MaxDelay: 5 * time.Second},
+			[]error{errTemp, errTemp, errTemp, errTemp, errTemp, errTemp}, 6,
+			[]time.Duration{
+				time.Second, 2 * time.Second, 4 * time.Second,
+				5 * time.Second, 5 * time.Second,
+			},
+			true, errTemp,
+		},
+		{
+			"not retryable",
+			gnlib.Retry{
+				Attempts:  5,
+				Retryable: func(err error) bool { return !errors.Is(err, errFatal) },
+			},
+			[]error{errTemp, errFatal, errTemp}, 2,
+			[]time.Duration{100 * time.Millisecond},
+			false, errFatal,
+		},
+		{
+			"context error", gnlib.Retry{Attempts: 5},
+			[]error{context.DeadlineExceeded}, 1, nil, false, context.DeadlineExceeded,
+		},
+		{
+			"max elapsed",
+			gnlib.Retry{Attempts: 10, BaseDelay: time.Second, MaxElapsed: 10 * time.Second},
+			[]error{errTemp, errTemp, errTemp, errTemp, errTemp, errTemp}, 4,
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			true, errTemp,
+		},
+	}
+
+	for _, v := range tests {
+		clock := newFakeClock()
+		sl := &sleepRecorder{clock: clock}
+		v.r.Clock = clock
+		v.r.Sleep = sl.Sleep
+		v.r.Jitter = noJitter
+		var calls int
+		err := v.r.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls > len(v.errs) {
+				return nil
+			}
+			return v.errs[calls-1]
+		})
+		assert.Equal(v.calls, calls, v.msg)
+		assert.Equal(v.delays, sl.delays, v.msg)
+		if v.err == nil {
+			assert.Nil(err, v.msg)
+			continue
+		}
+		assert.ErrorIs(err, v.err, v.msg)
+		var rErr *gnlib.RetryError
+		assert.Equal(v.isRetErr, errors.As(err, &rErr), v.msg)
+		if v.isRetErr {
+			assert.Equal(calls, rErr.Attempts, v.msg)
+		}
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	sl := &sleepRecorder{clock: clock}
+	r := gnlib.Retry{Attempts: 20, BaseDelay: time.Second, MaxDelay: 8 * time.Second,
+		Clock: clock, Sleep: sl.Sleep}
+	err := r.Do(context.Background(), func(context.Context) error {
+		return errors.New("temporary")
+	})
+	assert.NotNil(err)
+	assert.Len(sl.delays, 19)
+	var sum time.Duration
+	for i, d := range sl.delays {
+		assert.GreaterOrEqual(d, time.Duration(0))
+		assert.LessOrEqual(d, min(time.Second<<i, 8*time.Second))
+		sum += d
+	}
+	// full jitter gives different delays
+	assert.NotEqual(sum, time.Duration(0))
+	assert.NotEqual(sl.delays[len(sl.delays)-1], sl.delays[len(sl.delays)-2])
+}
+
+func TestRetryValue(t *testing.T) {
+	assert := assert.New(t)
+	var calls int
+	res, err := gnlib.RetryValue(context.Background(), gnlib.Retry{Jitter: noJitter,
+		Sleep: func(context.Context, time.Duration) error { return nil }},
+		func(context.Context) (string, error) {
+			calls++
+			if calls < 2 {
+				return "", errors.New("temporary")
+			}
+			return "ok", nil
+		})
+	assert.Nil(err)
+	assert.Equal("ok", res)
+	assert.Equal(2, calls)
+}
+
+func TestRetryCancel(t *testing.T) {
+	assert := assert.New(t)
+	errTemp := errors.New("temporary")
+	errStop := errors.New("stop")
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	r := gnlib.Retry{Attempts: 5, Clock: clock}
+
+	var calls int
+	done := make(chan error)
+	go func() {
+		done <- r.Do(ctx, func(context.Context) error {
+			calls++
+			return errTemp
+		})
+	}()
+	clock.WaitTimers(1)
+	cancel(errStop)
+	err := <-done
+	assert.Equal(1, calls)
+	assert.ErrorIs(err, errStop)
+	assert.ErrorIs(err, errTemp)
+
+	// cancelled context does not call the function
+	err = r.Do(ctx, func(context.Context) error {
+		calls++
+		return nil
+	})
+	assert.Equal(1, calls)
+	assert.ErrorIs(err, errStop)
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	r := gnlib.Retry{Attempts: 5, MaxElapsed: 5 * time.Second, Clock: clock}
+	var calls int
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- r.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			close(started)
+			// a hung call that stops only with its context
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+	}()
+	<-started
+	clock.Advance(5 * time.Second)
+	err := <-done
+	assert.Equal(1, calls)
+	var rErr *gnlib.RetryError
+	assert.ErrorAs(err, &rErr)
+	assert.Equal(1, rErr.Attempts)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestRetryClock(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	r := gnlib.Retry{BaseDelay: time.Second, Clock: clock, Jitter: noJitter}
+	var calls int
+	done := make(chan error)
+	go func() {
+		done <- r.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("temporary")
+			}
+			return nil
+		})
+	}()
+	clock.WaitTimers(1)
+	clock.Advance(time.Second)
+	clock.WaitTimers(1)
+	clock.Advance(2 * time.Second)
+	assert.Nil(<-done)
+	assert.Equal(3, calls)
+}
@@ -0,0 +1,127 @@
+package gnlib
+
+import "iter"
+
+// MapSeq returns an iterator that applies a function to each element of
+// seq. It is a lazy counterpart of Map.
+func MapSeq[T any, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeq returns an iterator over elements of seq for which filter
+// function returns true. It is a lazy counterpart of FilterFunc.
+func FilterSeq[T any](seq iter.Seq[T], f func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if f(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns an iterator over the first n elements of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n < 1 {
+			return
+		}
+		var i int
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns an iterator over elements of seq that go after the first
+// n elements.
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var i int
+		for v := range seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive slices of up to size
+// elements of seq. Every slice is newly allocated, so it can be kept by
+// the caller. Chunk panics if size is less than 1.
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("cannot be less than 1")
+	}
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for v := range seq {
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Zip returns an iterator over pairs of elements of a and b. The
+// iteration stops when either of the sequences ends.
+func Zip[T any, U any](a iter.Seq[T], b iter.Seq[U]) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for va := range a {
+			vb, ok := next()
+			if !ok || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over elements of seq and their indices.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var i int
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Collect gathers elements of seq into a new slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	var res []T
+	for v := range seq {
+		res = append(res, v)
+	}
+	return res
+}
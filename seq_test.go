@@ -0,0 +1,134 @@
+package gnlib_test
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// countSeq returns an endless sequence of integers starting from 0 and
+// records how many elements were generated.
+func countSeq(generated *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			*generated++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestMapSeq(t *testing.T) {
+	assert := assert.New(t)
+	seq := slices.Values([]string{"a", "b", "c"})
+	res := gnlib.Collect(gnlib.MapSeq(seq, strings.ToUpper))
+	assert.Equal([]string{"A", "B", "C"}, res)
+}
+
+func TestFilterSeq(t *testing.T) {
+	assert := assert.New(t)
+	seq := slices.Values([]string{"a", "b", "c"})
+	res := gnlib.Collect(gnlib.FilterSeq(seq, func(s string) bool {
+		return s != "b"
+	}))
+	assert.Equal([]string{"a", "c"}, res)
+}
+
+func TestTakeSkip(t *testing.T) {
+	assert := assert.New(t)
+	seq := slices.Values([]int{1, 2, 3, 4, 5})
+	tests := []struct {
+		msg string
+		seq iter.Seq[int]
+		res []int
+	}{
+		{"take", gnlib.Take(seq, 2), []int{1, 2}},
+		{"take 0", gnlib.Take(seq, 0), nil},
+		{"take more", gnlib.Take(seq, 10), []int{1, 2, 3, 4, 5}},
+		{"skip", gnlib.Skip(seq, 2), []int{3, 4, 5}},
+		{"skip 0", gnlib.Skip(seq, 0), []int{1, 2, 3, 4, 5}},
+		{"skip more", gnlib.Skip(seq, 10), nil},
+		{"skip take", gnlib.Take(gnlib.Skip(seq, 1), 3), []int{2, 3, 4}},
+	}
+
+	for _, v := range tests {
+		assert.Equal(v.res, gnlib.Collect(v.seq), v.msg)
+	}
+}
+
+func TestSeqLazy(t *testing.T) {
+	assert := assert.New(t)
+	var generated int
+	evens := gnlib.FilterSeq(countSeq(&generated), func(i int) bool {
+		return i%2 == 0
+	})
+	doubled := gnlib.MapSeq(evens, func(i int) int { return i * 10 })
+	res := gnlib.Collect(gnlib.Take(doubled, 3))
+	assert.Equal([]int{0, 20, 40}, res)
+	assert.Equal(5, generated)
+}
+
+func TestChunk(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg   string
+		input []int
+		size  int
+		res   [][]int
+	}{
+		{"normal", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"partial", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"empty", []int{}, 2, nil},
+		{"larger", []int{1, 2}, 5, [][]int{{1, 2}}},
+	}
+
+	for _, v := range tests {
+		res := gnlib.Collect(gnlib.Chunk(slices.Values(v.input), v.size))
+		assert.Equal(v.res, res, v.msg)
+	}
+
+	var generated int
+	res := gnlib.Collect(gnlib.Take(gnlib.Chunk(countSeq(&generated), 3), 2))
+	assert.Equal([][]int{{0, 1, 2}, {3, 4, 5}}, res)
+
+	assert.Panics(func() { gnlib.Chunk(slices.Values([]int{1}), 0) })
+}
+
+func TestZip(t *testing.T) {
+	assert := assert.New(t)
+	names := slices.Values([]string{"Bubo", "Puma", "Aus"})
+	var generated int
+	var res []string
+	for n, i := range gnlib.Zip(names, countSeq(&generated)) {
+		res = append(res, strings.Repeat(n, i))
+	}
+	assert.Equal([]string{"", "Puma", "AusAus"}, res)
+
+	short := slices.Values([]int{1})
+	var count int
+	for range gnlib.Zip(names, short) {
+		count++
+	}
+	assert.Equal(1, count)
+}
+
+func TestEnumerate(t *testing.T) {
+	assert := assert.New(t)
+	seq := slices.Values([]string{"a", "b", "c"})
+	var idx []int
+	var vals []string
+	for i, v := range gnlib.Enumerate(seq) {
+		idx = append(idx, i)
+		vals = append(vals, v)
+		if i == 1 {
+			break
+		}
+	}
+	assert.Equal([]int{0, 1}, idx)
+	assert.Equal([]string{"a", "b"}, vals)
+}
@@ -1,8 +1,245 @@
 package gnlib
 
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Set is a collection of unique comparable values.
 type Set[T comparable] map[T]struct{}
 
+// NewSet creates a Set from the given values.
+func NewSet[T comparable](vals ...T) Set[T] {
+	res := make(Set[T], len(vals))
+	for _, v := range vals {
+		res.Add(v)
+	}
+	return res
+}
+
+// SetFromSlice creates a Set from the elements of a slice.
+func SetFromSlice[T comparable](s []T) Set[T] {
+	return NewSet(s...)
+}
+
+// SetFromSeq creates a Set from the values of an iterator.
+func SetFromSeq[T comparable](seq iter.Seq[T]) Set[T] {
+	res := make(Set[T])
+	for v := range seq {
+		res.Add(v)
+	}
+	return res
+}
+
 func (s Set[T]) Add(v T)      { s[v] = struct{}{} }
 func (s Set[T]) Has(v T) bool { _, ok := s[v]; return ok }
 func (s Set[T]) Del(v T)      { delete(s, v) }
 func (s Set[T]) Len() int     { return len(s) }
+
+// Items returns elements of the set as a slice in no particular order.
+func (s Set[T]) Items() []T {
+	res := make([]T, 0, len(s))
+	for k := range s {
+		res = append(res, k)
+	}
+	return res
+}
+
+// Clone returns a copy of the set.
+func (s Set[T]) Clone() Set[T] {
+	res := make(Set[T], len(s))
+	for k := range s {
+		res.Add(k)
+	}
+	return res
+}
+
+// Union returns a new set with elements that are in s or in other.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	res := s.Clone()
+	for k := range other {
+		res.Add(k)
+	}
+	return res
+}
+
+// Intersection returns a new set with elements that are both in s
+// and in other.
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	small, big := s, other
+	if len(small) > len(big) {
+		small, big = big, small
+	}
+	res := make(Set[T])
+	for k := range small {
+		if big.Has(k) {
+			res.Add(k)
+		}
+	}
+	return res
+}
+
+// Difference returns a new set with elements of s that are not in other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	res := make(Set[T])
+	for k := range s {
+		if !other.Has(k) {
+			res.Add(k)
+		}
+	}
+	return res
+}
+
+// SymmetricDifference returns a new set with elements that are either
+// in s or in other, but not in both.
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	res := s.Difference(other)
+	for k := range other {
+		if !s.Has(k) {
+			res.Add(k)
+		}
+	}
+	return res
+}
+
+// IsSubset returns true if all elements of s are in other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for k := range s {
+		if !other.Has(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all elements of other are in s.
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal returns true if s and other contain the same elements.
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// SortedItems returns elements of a set of ordered values as a sorted
+// slice.
+func SortedItems[T cmp.Ordered](s Set[T]) []T {
+	res := s.Items()
+	slices.Sort(res)
+	return res
+}
+
+// All returns an iterator over elements of the set in no particular
+// order. It allows to use the set with range-over-func loops.
+func (s Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for k := range s {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// String implements fmt.Stringer interface. Elements are printed in
+// the deterministic order described in MarshalJSON.
+func (s Set[T]) String() string {
+	items := s.sortedAny()
+	strs := make([]string, len(items))
+	for i := range items {
+		strs[i] = fmt.Sprint(items[i])
+	}
+	return "{" + strings.Join(strs, " ") + "}"
+}
+
+// MarshalJSON implements json.Marshaller interface and converts the set
+// into a JSON array. Numbers and strings are sorted in their natural
+// order, other elements are sorted by their JSON representation.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.sortedAny())
+}
+
+// UnmarshalJSON implements json.Unmarshaller interface and converts
+// a JSON array into a set. For backward compatibility it also accepts
+// a JSON object where elements are keys.
+func (s *Set[T]) UnmarshalJSON(bs []byte) error {
+	bs = bytes.TrimSpace(bs)
+	if bytes.Equal(bs, []byte("null")) {
+		*s = nil
+		return nil
+	}
+
+	var items []T
+	if len(bs) > 0 && bs[0] == '{' {
+		var m map[T]struct{}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			return err
+		}
+		*s = Set[T](m)
+		return nil
+	}
+	if err := json.Unmarshal(bs, &items); err != nil {
+		return err
+	}
+	*s = NewSet(items...)
+	return nil
+}
+
+// sortedAny returns elements of the set in a deterministic order.
+func (s Set[T]) sortedAny() []T {
+	res := s.Items()
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		sortByKey(res, func(v T) int64 { return reflect.ValueOf(v).Int() },
+			cmp.Compare[int64])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		sortByKey(res, func(v T) uint64 { return reflect.ValueOf(v).Uint() },
+			cmp.Compare[uint64])
+	case reflect.Float32, reflect.Float64:
+		sortByKey(res, func(v T) float64 { return reflect.ValueOf(v).Float() },
+			cmp.Compare[float64])
+	case reflect.String:
+		sortByKey(res, func(v T) string { return reflect.ValueOf(v).String() },
+			cmp.Compare[string])
+	default:
+		sortByKey(res, func(v T) []byte {
+			bs, _ := json.Marshal(v)
+			return bs
+		}, bytes.Compare)
+	}
+	return res
+}
+
+// sortByKey sorts a slice by keys that are computed once for every
+// element.
+func sortByKey[T, K any](s []T, key func(T) K, cmpKey func(a, b K) int) {
+	type keyed struct {
+		val T
+		key K
+	}
+	items := make([]keyed, len(s))
+	for i, v := range s {
+		items[i] = keyed{val: v, key: key(v)}
+	}
+	slices.SortFunc(items, func(a, b keyed) int {
+		return cmpKey(a.key, b.key)
+	})
+	for i := range items {
+		s[i] = items[i].val
+	}
+}
@@ -1,8 +1,10 @@
 package gnlib_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnlib"
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +74,169 @@ func TestSet_WithDifferentTypes(t *testing.T) {
 	assert.True(s.Has(point{1, 2}))
 	assert.Equal(2, s.Len())
 }
+
+func TestNewSet(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSet(3, 1, 3, 2)
+	assert.Equal(3, s.Len())
+	assert.True(s.Has(3))
+
+	s2 := gnlib.SetFromSlice([]string{"a", "b", "a"})
+	assert.Equal([]string{"a", "b"}, gnlib.SortedItems(s2))
+
+	assert.Equal(0, gnlib.NewSet[int]().Len())
+	assert.Empty(gnlib.NewSet[int]().Items())
+}
+
+func TestSet_Items(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSet(3, 1, 2)
+	assert.ElementsMatch([]int{1, 2, 3}, s.Items())
+	assert.Equal([]int{1, 2, 3}, gnlib.SortedItems(s))
+}
+
+func TestSet_Clone(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSet(1, 2)
+	c := s.Clone()
+	c.Add(3)
+	assert.Equal(2, s.Len())
+	assert.Equal(3, c.Len())
+}
+
+func TestSet_Algebra(t *testing.T) {
+	assert := assert.New(t)
+	a := gnlib.NewSet(1, 2, 3, 4)
+	b := gnlib.NewSet(3, 4, 5)
+	empty := gnlib.NewSet[int]()
+
+	tests := []struct {
+		msg string
+		res gnlib.Set[int]
+		exp []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"union empty", a.Union(empty), []int{1, 2, 3, 4}},
+		{"intersection", a.Intersection(b), []int{3, 4}},
+		{"intersection rev", b.Intersection(a), []int{3, 4}},
+		{"intersection empty", a.Intersection(empty), []int{}},
+		{"difference", a.Difference(b), []int{1, 2}},
+		{"difference rev", b.Difference(a), []int{5}},
+		{"sym difference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"sym difference rev", b.SymmetricDifference(a), []int{1, 2, 5}},
+	}
+
+	for _, v := range tests {
+		assert.Equal(v.exp, gnlib.SortedItems(v.res), v.msg)
+	}
+	// operands are not modified
+	assert.Equal([]int{1, 2, 3, 4}, gnlib.SortedItems(a))
+	assert.Equal([]int{3, 4, 5}, gnlib.SortedItems(b))
+}
+
+func TestSet_Subset(t *testing.T) {
+	assert := assert.New(t)
+	a := gnlib.NewSet(1, 2, 3)
+	b := gnlib.NewSet(1, 2)
+	empty := gnlib.NewSet[int]()
+
+	assert.True(b.IsSubset(a))
+	assert.False(a.IsSubset(b))
+	assert.True(a.IsSuperset(b))
+	assert.False(b.IsSuperset(a))
+	assert.True(empty.IsSubset(a))
+	assert.True(a.IsSubset(a))
+
+	assert.True(a.Equal(gnlib.NewSet(3, 2, 1)))
+	assert.False(a.Equal(b))
+	assert.False(a.Equal(gnlib.NewSet(1, 2, 4)))
+	assert.True(empty.Equal(gnlib.Set[int]{}))
+}
+
+func TestSet_All(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSet(1, 2, 3)
+	var res []int
+	for v := range s.All() {
+		res = append(res, v)
+	}
+	assert.ElementsMatch([]int{1, 2, 3}, res)
+
+	var count int
+	for range s.All() {
+		count++
+		break
+	}
+	assert.Equal(1, count)
+
+	s2 := gnlib.SetFromSeq(s.All())
+	assert.True(s.Equal(s2))
+}
+
+func TestSet_String(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("{1 2 10}", gnlib.NewSet(10, 2, 1).String())
+	assert.Equal("{a b}", gnlib.NewSet("b", "a").String())
+	assert.Equal("{}", gnlib.NewSet[int]().String())
+
+	type code string
+	assert.Equal("{1 3 20}", gnlib.NewSet[uint8](20, 3, 1).String())
+	assert.Equal("{-0.5 1.5 2}", gnlib.NewSet(2, -0.5, 1.5).String())
+	assert.Equal("{ICN ICZN}", gnlib.NewSet[code]("ICZN", "ICN").String())
+}
+
+func TestSet_JSON(t *testing.T) {
+	assert := assert.New(t)
+	type point struct{ X, Y int }
+	type data struct {
+		Ints   gnlib.Set[int]    `json:"ints"`
+		Strs   gnlib.Set[string] `json:"strs"`
+		Points gnlib.Set[point]  `json:"points"`
+		Empty  gnlib.Set[int]    `json:"empty,omitempty"`
+	}
+	d := data{
+		Ints:   gnlib.NewSet(10, 2, -1),
+		Strs:   gnlib.NewSet("b", "c", "a"),
+		Points: gnlib.NewSet(point{2, 1}, point{1, 2}),
+	}
+	exp := `{"ints":[-1,2,10],"strs":["a","b","c"],` +
+		`"points":[{"X":1,"Y":2},{"X":2,"Y":1}]}`
+
+	for range 5 {
+		res, err := json.Marshal(d)
+		assert.Nil(err)
+		assert.Equal(exp, string(res))
+	}
+
+	enc := gnfmt.GNjson{}
+	res, err := enc.Encode(d)
+	assert.Nil(err)
+	assert.Equal(exp, string(res))
+
+	var d2 data
+	err = enc.Decode(res, &d2)
+	assert.Nil(err)
+	assert.True(d.Ints.Equal(d2.Ints))
+	assert.True(d.Strs.Equal(d2.Strs))
+	assert.True(d.Points.Equal(d2.Points))
+	assert.Nil(d2.Empty)
+}
+
+func TestSet_UnmarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+	var s gnlib.Set[int]
+	err := json.Unmarshal([]byte(`[3, 1, 3]`), &s)
+	assert.Nil(err)
+	assert.Equal([]int{1, 3}, gnlib.SortedItems(s))
+
+	err = json.Unmarshal([]byte(`{"5": {}, "7": {}}`), &s)
+	assert.Nil(err)
+	assert.Equal([]int{5, 7}, gnlib.SortedItems(s))
+
+	err = json.Unmarshal([]byte(`null`), &s)
+	assert.Nil(err)
+	assert.Nil(s)
+
+	err = json.Unmarshal([]byte(`["a"]`), &s)
+	assert.NotNil(err)
+}
@@ -0,0 +1,138 @@
+package gnlib
+
+import (
+	"hash/maphash"
+	"iter"
+	"sync"
+)
+
+// shardsNum is the number of independently locked parts of SyncMap.
+const shardsNum = 32
+
+// SyncMap is a generic map that is safe for concurrent use. Keys are
+// distributed among several independently locked shards, so goroutines
+// that work with different keys rarely block each other.
+//
+// SyncMap must be created with NewSyncMap.
+type SyncMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards [shardsNum]syncShard[K, V]
+}
+
+type syncShard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// NewSyncMap creates an empty SyncMap.
+func NewSyncMap[K comparable, V any]() *SyncMap[K, V] {
+	res := &SyncMap[K, V]{seed: maphash.MakeSeed()}
+	for i := range res.shards {
+		res.shards[i].m = make(map[K]V)
+	}
+	return res
+}
+
+func (m *SyncMap[K, V]) shard(k K) *syncShard[K, V] {
+	h := maphash.Comparable(m.seed, k)
+	return &m.shards[h%shardsNum]
+}
+
+// Load returns the value stored for the key and true, or the zero value
+// and false if there is no such key.
+func (m *SyncMap[K, V]) Load(k K) (V, bool) {
+	sh := m.shard(k)
+	sh.RLock()
+	defer sh.RUnlock()
+	v, ok := sh.m[k]
+	return v, ok
+}
+
+// Store sets the value for the key.
+func (m *SyncMap[K, V]) Store(k K, v V) {
+	sh := m.shard(k)
+	sh.Lock()
+	defer sh.Unlock()
+	sh.m[k] = v
+}
+
+// LoadOrStore returns the existing value for the key and true if the key
+// is present. Otherwise, it stores the given value and returns it with
+// false. The check and the store happen atomically.
+func (m *SyncMap[K, V]) LoadOrStore(k K, v V) (V, bool) {
+	sh := m.shard(k)
+	sh.Lock()
+	defer sh.Unlock()
+	if res, ok := sh.m[k]; ok {
+		return res, true
+	}
+	sh.m[k] = v
+	return v, false
+}
+
+// Has returns true if the key is present.
+func (m *SyncMap[K, V]) Has(k K) bool {
+	_, ok := m.Load(k)
+	return ok
+}
+
+// Del removes the key from the map.
+func (m *SyncMap[K, V]) Del(k K) {
+	sh := m.shard(k)
+	sh.Lock()
+	defer sh.Unlock()
+	delete(sh.m, k)
+}
+
+// Len returns the number of keys in the map.
+func (m *SyncMap[K, V]) Len() int {
+	var res int
+	for i := range m.shards {
+		m.shards[i].RLock()
+		res += len(m.shards[i].m)
+		m.shards[i].RUnlock()
+	}
+	return res
+}
+
+// Snapshot returns a copy of the map data. The copy is consistent, all
+// shards are locked while it is made.
+func (m *SyncMap[K, V]) Snapshot() map[K]V {
+	for i := range m.shards {
+		m.shards[i].RLock()
+	}
+	var l int
+	for i := range m.shards {
+		l += len(m.shards[i].m)
+	}
+	res := make(map[K]V, l)
+	for i := range m.shards {
+		for k, v := range m.shards[i].m {
+			res[k] = v
+		}
+		m.shards[i].RUnlock()
+	}
+	return res
+}
+
+// Keys returns keys of the map in no particular order.
+func (m *SyncMap[K, V]) Keys() []K {
+	snap := m.Snapshot()
+	res := make([]K, 0, len(snap))
+	for k := range snap {
+		res = append(res, k)
+	}
+	return res
+}
+
+// All returns an iterator over a snapshot of the map taken when the
+// iteration starts. The map can be modified during the iteration.
+func (m *SyncMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.Snapshot() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package gnlib_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewSyncMap[string, int]()
+	assert.Equal(0, m.Len())
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	v, ok := m.Load("a")
+	assert.True(ok)
+	assert.Equal(1, v)
+	_, ok = m.Load("c")
+	assert.False(ok)
+	assert.True(m.Has("b"))
+
+	v, loaded := m.LoadOrStore("a", 10)
+	assert.True(loaded)
+	assert.Equal(1, v)
+	v, loaded = m.LoadOrStore("c", 3)
+	assert.False(loaded)
+	assert.Equal(3, v)
+
+	m.Del("b")
+	m.Del("nope")
+	assert.False(m.Has("b"))
+	assert.Equal(2, m.Len())
+	assert.ElementsMatch([]string{"a", "c"}, m.Keys())
+	assert.Equal(map[string]int{"a": 1, "c": 3}, m.Snapshot())
+}
+
+func TestSyncMap_All(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewSyncMap[int, int]()
+	for i := range 100 {
+		m.Store(i, i*2)
+	}
+	var count int
+	for k, v := range m.All() {
+		assert.Equal(k*2, v)
+		// modification during iteration must not deadlock
+		m.Store(k+1000, v)
+		count++
+	}
+	assert.Equal(100, count)
+	assert.Equal(200, m.Len())
+}
+
+func TestSyncMap_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+	m := gnlib.NewSyncMap[int, int]()
+	var wg sync.WaitGroup
+	var stored atomic.Int64
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				if _, loaded := m.LoadOrStore(i, w); !loaded {
+					stored.Add(1)
+				}
+				m.Has(i)
+				if i%10 == 0 {
+					m.Len()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(int64(1000), stored.Load())
+	assert.Equal(1000, m.Len())
+}
+
+func TestSyncSet(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSyncSet(1, 2, 2)
+	assert.Equal(2, s.Len())
+	s.Add(3)
+	assert.True(s.Has(3))
+	s.Del(1)
+	assert.False(s.Has(1))
+
+	assert.False(s.AddIfAbsent(2))
+	assert.True(s.AddIfAbsent(4))
+	assert.ElementsMatch([]int{2, 3, 4}, s.Items())
+	assert.Equal([]int{2, 3, 4}, gnlib.SortedItems(s.Set()))
+
+	var res []int
+	for v := range s.All() {
+		res = append(res, v)
+	}
+	assert.ElementsMatch([]int{2, 3, 4}, res)
+}
+
+func TestSyncSet_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+	s := gnlib.NewSyncSet[int]()
+	var wg sync.WaitGroup
+	var added atomic.Int64
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				if s.AddIfAbsent(i) {
+					added.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(int64(1000), added.Load())
+	assert.Equal(1000, s.Len())
+}
+
+// mutexMap is a map guarded by a single mutex, it is used as a baseline
+// in benchmarks.
+type mutexMap[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+func (m *mutexMap[K, V]) Load(k K) (V, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *mutexMap[K, V]) Store(k K, v V) {
+	m.Lock()
+	defer m.Unlock()
+	m.m[k] = v
+}
+
+func BenchmarkSyncMap(b *testing.B) {
+	b.Run("SyncMap", func(b *testing.B) {
+		m := gnlib.NewSyncMap[int, int]()
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+			for pb.Next() {
+				i++
+				if i%4 == 0 {
+					m.Store(i%10_000, i)
+				} else {
+					m.Load(i % 10_000)
+				}
+			}
+		})
+	})
+
+	b.Run("MutexMap", func(b *testing.B) {
+		m := &mutexMap[int, int]{m: make(map[int]int)}
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+			for pb.Next() {
+				i++
+				if i%4 == 0 {
+					m.Store(i%10_000, i)
+				} else {
+					m.Load(i % 10_000)
+				}
+			}
+		})
+	})
+}
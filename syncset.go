@@ -0,0 +1,49 @@
+package gnlib
+
+import "iter"
+
+// SyncSet is a set that is safe for concurrent use. It has the same
+// methods as Set, and adds AddIfAbsent for atomic check-and-add.
+//
+// SyncSet must be created with NewSyncSet.
+type SyncSet[T comparable] struct {
+	m *SyncMap[T, struct{}]
+}
+
+// NewSyncSet creates a SyncSet from the given values.
+func NewSyncSet[T comparable](vals ...T) *SyncSet[T] {
+	res := &SyncSet[T]{m: NewSyncMap[T, struct{}]()}
+	for _, v := range vals {
+		res.Add(v)
+	}
+	return res
+}
+
+func (s *SyncSet[T]) Add(v T)      { s.m.Store(v, struct{}{}) }
+func (s *SyncSet[T]) Has(v T) bool { return s.m.Has(v) }
+func (s *SyncSet[T]) Del(v T)      { s.m.Del(v) }
+func (s *SyncSet[T]) Len() int     { return s.m.Len() }
+
+// AddIfAbsent adds the value and returns true if the value was not in
+// the set. If the value was already there, it returns false. Only one of
+// concurrent callers with the same value gets true.
+func (s *SyncSet[T]) AddIfAbsent(v T) bool {
+	_, loaded := s.m.LoadOrStore(v, struct{}{})
+	return !loaded
+}
+
+// Items returns elements of the set as a slice in no particular order.
+func (s *SyncSet[T]) Items() []T {
+	return s.m.Keys()
+}
+
+// Set returns a snapshot of the set as a Set.
+func (s *SyncSet[T]) Set() Set[T] {
+	return Set[T](s.m.Snapshot())
+}
+
+// All returns an iterator over a snapshot of the set taken when the
+// iteration starts. The set can be modified during the iteration.
+func (s *SyncSet[T]) All() iter.Seq[T] {
+	return s.Set().All()
+}
@@ -0,0 +1,260 @@
+package gnlib
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a version that follows Semantic Versioning 2.0
+// (https://semver.org), for example "v1.2.3-beta.1+20240101".
+type Version struct {
+	// Major, Minor and Patch are the numeric parts of the version.
+	Major, Minor, Patch uint64
+
+	// Pre contains dot-separated identifiers of a pre-release,
+	// for example ["beta", "1"] for "v1.2.3-beta.1".
+	Pre []string
+
+	// Build contains dot-separated identifiers of build metadata. They
+	// are ignored when versions are compared.
+	Build []string
+}
+
+// VersionError is returned when a string cannot be parsed as a Version.
+type VersionError struct {
+	// Input is the string that failed to parse.
+	Input string
+
+	// Reason describes what is wrong with the input.
+	Reason string
+}
+
+// Error implements error interface.
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("invalid version %q: %s", e.Input, e.Reason)
+}
+
+// ParseVersion parses a Semantic Versioning 2.0 string with or without
+// "v" prefix. Numbers cannot have leading zeros, and all three numbers
+// are required. It returns *VersionError if the string is not a valid
+// version.
+func ParseVersion(s string) (Version, error) {
+	var res Version
+	fail := func(reason string, args ...any) (Version, error) {
+		return Version{}, &VersionError{
+			Input:  s,
+			Reason: fmt.Sprintf(reason, args...),
+		}
+	}
+
+	rest := strings.TrimPrefix(s, "v")
+	if rest == "" {
+		return fail("empty version")
+	}
+	if i := strings.IndexByte(rest, '+'); i > -1 {
+		build := rest[i+1:]
+		rest = rest[:i]
+		res.Build = strings.Split(build, ".")
+		for _, id := range res.Build {
+			if err := checkIdent(id); err != "" {
+				return fail("build metadata %s", err)
+			}
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i > -1 {
+		pre := rest[i+1:]
+		rest = rest[:i]
+		res.Pre = strings.Split(pre, ".")
+		for _, id := range res.Pre {
+			if err := checkIdent(id); err != "" {
+				return fail("pre-release %s", err)
+			}
+			if isNumeric(id) && len(id) > 1 && id[0] == '0' {
+				return fail("pre-release identifier %q has leading zero", id)
+			}
+		}
+	}
+
+	nums := strings.Split(rest, ".")
+	if len(nums) != 3 {
+		return fail("expected MAJOR.MINOR.PATCH")
+	}
+	parts := []*uint64{&res.Major, &res.Minor, &res.Patch}
+	for i, n := range nums {
+		if !isNumeric(n) {
+			return fail("%q is not a number", n)
+		}
+		if len(n) > 1 && n[0] == '0' {
+			return fail("number %q has leading zero", n)
+		}
+		v, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return fail("number %q is too large", n)
+		}
+		*parts[i] = v
+	}
+	return res, nil
+}
+
+// MustParseVersion is like ParseVersion, but panics if the string
+// cannot be parsed. It is meant for constants known to be valid.
+func MustParseVersion(s string) Version {
+	res, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// ParsePartialVersion parses a version that can omit minor and patch
+// numbers, for example "v2" or "1.4". Missing numbers are set to 0.
+// It also returns how many numbers were given. Pre-release and build
+// metadata are allowed only with all three numbers. It returns
+// *VersionError if the string is not a valid version.
+func ParsePartialVersion(s string) (Version, int, error) {
+	core := strings.TrimPrefix(s, "v")
+	var suffix string
+	if i := strings.IndexAny(core, "-+"); i > -1 {
+		core, suffix = core[:i], core[i:]
+	}
+	n := strings.Count(core, ".") + 1
+	if n < 3 && suffix != "" {
+		return Version{}, 0, &VersionError{
+			Input:  s,
+			Reason: "pre-release or build needs all three numbers",
+		}
+	}
+	for i := n; i < 3; i++ {
+		core += ".0"
+	}
+	res, err := ParseVersion(core + suffix)
+	if err != nil {
+		// report the original input instead of the padded one
+		if vErr, ok := err.(*VersionError); ok {
+			vErr.Input = s
+		}
+		return Version{}, 0, err
+	}
+	return res, n, nil
+}
+
+// String returns the version with "v" prefix.
+func (v Version) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Pre) > 0 {
+		sb.WriteString("-" + strings.Join(v.Pre, "."))
+	}
+	if len(v.Build) > 0 {
+		sb.WriteString("+" + strings.Join(v.Build, "."))
+	}
+	return sb.String()
+}
+
+// IsPrerelease returns true if the version has pre-release identifiers.
+func (v Version) IsPrerelease() bool {
+	return len(v.Pre) > 0
+}
+
+// Compare returns -1 if v has lower precedence than other, 1 if it has
+// higher precedence, and 0 if the precedence is the same. Precedence
+// follows Semantic Versioning 2.0: a pre-release is lower than its
+// normal version (v1.0.0-rc.1 < v1.0.0), numeric identifiers are
+// compared as numbers and are lower than alphanumeric ones, and build
+// metadata is ignored.
+func (v Version) Compare(other Version) int {
+	if res := cmp.Compare(v.Major, other.Major); res != 0 {
+		return res
+	}
+	if res := cmp.Compare(v.Minor, other.Minor); res != 0 {
+		return res
+	}
+	if res := cmp.Compare(v.Patch, other.Patch); res != 0 {
+		return res
+	}
+	switch {
+	case len(v.Pre) == 0 && len(other.Pre) == 0:
+		return 0
+	case len(v.Pre) == 0:
+		return 1
+	case len(other.Pre) == 0:
+		return -1
+	}
+	for i := range min(len(v.Pre), len(other.Pre)) {
+		if res := cmpIdent(v.Pre[i], other.Pre[i]); res != 0 {
+			return res
+		}
+	}
+	return cmp.Compare(len(v.Pre), len(other.Pre))
+}
+
+// Less returns true if v has lower precedence than other.
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (v *Version) UnmarshalText(bs []byte) error {
+	res, err := ParseVersion(string(bs))
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+// cmpIdent compares two pre-release identifiers.
+func cmpIdent(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		// numbers have no leading zeros, a longer one is larger
+		if res := cmp.Compare(len(a), len(b)); res != 0 {
+			return res
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// checkIdent returns a description of a problem with an identifier, or an
+// empty string if the identifier is valid.
+func checkIdent(id string) string {
+	if id == "" {
+		return "has an empty identifier"
+	}
+	for _, r := range id {
+		if !isIdentRune(r) {
+			return fmt.Sprintf("identifier %q has invalid character %q", id, r)
+		}
+	}
+	return ""
+}
+
+func isIdentRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' ||
+		r >= 'A' && r <= 'Z' || r == '-'
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package gnlib_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gnames/gnlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, input string
+		ver        gnlib.Version
+		str        string
+	}{
+		{"plain", "1.2.3", gnlib.Version{Major: 1, Minor: 2, Patch: 3}, "v1.2.3"},
+		{"prefix", "v0.10.0", gnlib.Version{Minor: 10}, "v0.10.0"},
+		{
+			"pre", "v1.2.0-beta.1",
+			gnlib.Version{Major: 1, Minor: 2, Pre: []string{"beta", "1"}},
+			"v1.2.0-beta.1",
+		},
+		{
+			"pre hyphen", "1.0.0-x-y.0",
+			gnlib.Version{Major: 1, Pre: []string{"x-y", "0"}},
+			"v1.0.0-x-y.0",
+		},
+		{
+			"build", "v1.0.0+20240101.sha.0abc",
+			gnlib.Version{Major: 1, Build: []string{"20240101", "sha", "0abc"}},
+			"v1.0.0+20240101.sha.0abc",
+		},
+		{
+			"pre and build", "v1.0.0-rc.1+exp-1",
+			gnlib.Version{Major: 1, Pre: []string{"rc", "1"}, Build: []string{"exp-1"}},
+			"v1.0.0-rc.1+exp-1",
+		},
+	}
+
+	for _, v := range tests {
+		res, err := gnlib.ParseVersion(v.input)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.ver, res, v.msg)
+		assert.Equal(v.str, res.String(), v.msg)
+	}
+}
+
+func TestParseVersionErr(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, input, err string
+	}{
+		{"empty", "", `invalid version "": empty version`},
+		{"only v", "v", `invalid version "v": empty version`},
+		{"short", "v1.2", `invalid version "v1.2": expected MAJOR.MINOR.PATCH`},
+		{"long", "v1.2.3.4", `invalid version "v1.2.3.4": expected MAJOR.MINOR.PATCH`},
+		{"wildcard", "v1.2.x", `invalid version "v1.2.x": "x" is not a number`},
+		{"leading zero", "v1.02.3", `invalid version "v1.02.3": number "02" has leading zero`},
+		{"negative", "v1.-2.3", `invalid version "v1.-2.3": expected MAJOR.MINOR.PATCH`},
+		{"empty pre", "v1.2.3-", `invalid version "v1.2.3-": pre-release has an empty identifier`},
+		{
+			"pre zero", "v1.2.3-beta.01",
+			`invalid version "v1.2.3-beta.01": pre-release identifier "01" has leading zero`,
+		},
+		{
+			"bad char", "v1.2.3-beta_1",
+			`invalid version "v1.2.3-beta_1": pre-release identifier "beta_1" has invalid character '_'`,
+		},
+		{"empty build", "v1.2.3+a..b", `invalid version "v1.2.3+a..b": build metadata has an empty identifier`},
+		{
+			"too large", "v99999999999999999999.0.0",
+			`invalid version "v99999999999999999999.0.0": number "99999999999999999999" is too large`,
+		},
+		{"space", " v1.2.3", `invalid version " v1.2.3": " v1" is not a number`},
+	}
+
+	for _, v := range tests {
+		_, err := gnlib.ParseVersion(v.input)
+		var vErr *gnlib.VersionError
+		assert.ErrorAs(err, &vErr, v.msg)
+		assert.Equal(v.err, err.Error(), v.msg)
+	}
+
+	assert.Panics(func() { gnlib.MustParseVersion("v1") })
+	assert.NotPanics(func() { gnlib.MustParseVersion("v1.0.0") })
+}
+
+func TestParsePartialVersion(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, input string
+		ver        gnlib.Version
+		n          int
+		err        string
+	}{
+		{"major", "v2", gnlib.Version{Major: 2}, 1, ""},
+		{"minor", "1.4", gnlib.Version{Major: 1, Minor: 4}, 2, ""},
+		{"full", "v1.2.3", gnlib.Version{Major: 1, Minor: 2, Patch: 3}, 3, ""},
+		{
+			"full pre", "v1.2.3-rc.1",
+			gnlib.Version{Major: 1, Minor: 2, Patch: 3, Pre: []string{"rc", "1"}},
+			3, "",
+		},
+		{
+			"partial pre", "v1-beta", gnlib.Version{}, 0,
+			`invalid version "v1-beta": pre-release or build needs all three numbers`,
+		},
+		{
+			"partial build", "v1.2+dev", gnlib.Version{}, 0,
+			`invalid version "v1.2+dev": pre-release or build needs all three numbers`,
+		},
+		{"bad", "v1.x", gnlib.Version{}, 0, `invalid version "v1.x": "x" is not a number`},
+	}
+
+	for _, v := range tests {
+		res, n, err := gnlib.ParsePartialVersion(v.input)
+		if v.err != "" {
+			assert.EqualError(err, v.err, v.msg)
+			continue
+		}
+		assert.Nil(err, v.msg)
+		assert.Equal(v.ver, res, v.msg)
+		assert.Equal(v.n, n, v.msg)
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	assert := assert.New(t)
+	// SemVer 2.0 example of precedence
+	ordered := []string{
+		"v1.0.0-alpha", "v1.0.0-alpha.1", "v1.0.0-alpha.beta", "v1.0.0-beta",
+		"v1.0.0-beta.2", "v1.0.0-beta.11", "v1.0.0-rc.1", "v1.0.0",
+		"v1.0.1", "v1.2.0-beta.1", "v1.2.0", "v1.10.0", "v2.0.0",
+	}
+	for i := range len(ordered) - 1 {
+		a := gnlib.MustParseVersion(ordered[i])
+		b := gnlib.MustParseVersion(ordered[i+1])
+		assert.Equal(-1, a.Compare(b), ordered[i])
+		assert.Equal(1, b.Compare(a), ordered[i])
+		assert.True(a.Less(b), ordered[i])
+		assert.False(b.Less(a), ordered[i])
+	}
+
+	tests := []struct {
+		a, b string
+		res  int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2.3+a", "v1.2.3+b", 0},
+		{"v1.2.0-beta.1", "v1.2.0", -1},
+		{"v1.0.0-1", "v1.0.0-a", -1},
+		{"v1.0.0-99999999999999999999999", "v1.0.0-100000000000000000000000", -1},
+	}
+	for _, v := range tests {
+		a := gnlib.MustParseVersion(v.a)
+		b := gnlib.MustParseVersion(v.b)
+		assert.Equal(v.res, a.Compare(b), v.a+" "+v.b)
+	}
+	assert.True(gnlib.MustParseVersion("v1.0.0-rc.1").IsPrerelease())
+	assert.False(gnlib.MustParseVersion("v1.0.0+rc.1").IsPrerelease())
+}
+
+func TestVersionJSON(t *testing.T) {
+	assert := assert.New(t)
+	type app struct {
+		Version gnlib.Version `json:"version"`
+	}
+	a := app{Version: gnlib.MustParseVersion("1.2.3-rc.1")}
+	bs, err := json.Marshal(a)
+	assert.Nil(err)
+	assert.Equal(`{"version":"v1.2.3-rc.1"}`, string(bs))
+
+	var a2 app
+	err = json.Unmarshal(bs, &a2)
+	assert.Nil(err)
+	assert.Equal(a, a2)
+
+	err = json.Unmarshal([]byte(`{"version":"v1.2"}`), &a2)
+	var vErr *gnlib.VersionError
+	assert.ErrorAs(err, &vErr)
+}